@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/k1LoW/git-wt/internal/git"
+)
+
+// printCopySummary writes a one-line summary of the files copied into a new
+// worktree, e.g. "Copied 1204 files (38.2 MB), symlinked 1 directory". It
+// prints nothing if there was nothing to copy.
+func printCopySummary(w io.Writer, r *git.CopyResult) {
+	if r == nil || r.Copied+r.Hardlinked+r.Symlinks+r.Skipped+len(r.Failures) == 0 {
+		return
+	}
+	parts := []string{fmt.Sprintf("Copied %s (%s)", plural(r.Copied, "file"), formatBytes(r.Bytes))}
+	if r.Hardlinked > 0 {
+		parts = append(parts, "hard linked "+plural(r.Hardlinked, "file"))
+	}
+	if r.Symlinks > 0 {
+		parts = append(parts, "symlinked "+plural(r.Symlinks, "directory"))
+	}
+	if r.Skipped > 0 {
+		parts = append(parts, "skipped "+plural(r.Skipped, "file")+" by nocopy")
+	}
+	if len(r.Failures) > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", len(r.Failures)))
+	}
+	fmt.Fprintln(w, strings.Join(parts, ", "))
+}
+
+// plural formats a count with a singular or plural noun.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if strings.HasSuffix(noun, "y") {
+		return fmt.Sprintf("%d %sies", n, strings.TrimSuffix(noun, "y"))
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// formatBytes formats a size in bytes with a decimal unit, e.g. "38.2 MB".
+func formatBytes(n int64) string {
+	if n < 1000 {
+		return fmt.Sprintf("%d B", n)
+	}
+	size := float64(n)
+	for _, unit := range []string{"kB", "MB", "GB", "TB"} {
+		size /= 1000
+		if size < 1000 || unit == "TB" {
+			return fmt.Sprintf("%.1f %s", size, unit)
+		}
+	}
+	return ""
+}
+
+// copyFromDefault is the wt.copyfrom value that copies files from the
+// worktree of the default branch.
+const copyFromDefault = "default"
+
+// resolveCopyFrom returns the path of the worktree to copy files from for a
+// --copy-from or wt.copyfrom value, or "" to copy from the current worktree.
+func resolveCopyFrom(ctx context.Context, from string) (string, error) {
+	if from == "" {
+		return "", nil
+	}
+	if from == copyFromDefault {
+		branch, err := git.DefaultBranch(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get default branch: %w", err)
+		}
+		wt, err := git.FindWorktreeByBranch(ctx, branch)
+		if err != nil {
+			return "", fmt.Errorf("failed to find worktree: %w", err)
+		}
+		if wt == nil {
+			fmt.Fprintf(os.Stderr, "warning: default branch %q has no worktree to copy files from, using the current worktree\n", branch)
+			return "", nil
+		}
+		return wt.Path, nil
+	}
+	wt, err := git.FindWorktreeByBranchOrDir(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("failed to find worktree: %w", err)
+	}
+	if wt == nil {
+		return "", fmt.Errorf("no worktree found for %q to copy files from", from)
+	}
+	return wt.Path, nil
+}
@@ -0,0 +1,555 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/k1LoW/git-wt/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// deleteTarget is a target of -d/-D that has been resolved and validated by
+// planDelete. Nothing has been modified yet.
+type deleteTarget struct {
+	query        string        // Argument as given on the command line
+	wt           *git.Worktree // nil when only the branch is deleted
+	wtDir        string        // Worktree directory name (worktree targets only)
+	branch       string        // Branch to delete
+	branchExists bool          // Whether branch exists as a local branch
+	isDefault    bool          // Whether branch is the default branch
+	forceBranch  bool          // Delete the branch with 'git branch -D'
+	isCurrent    bool          // Whether the worktree is the current one
+	deleteHooks  []string      // Delete hooks for the branch of the worktree
+}
+
+// deleteResult is the outcome of one target, reported by --keep-going.
+type deleteResult struct {
+	query  string
+	result string
+	err    error
+}
+
+func deleteWorktrees(ctx context.Context, cmd *cobra.Command, branches []string, force bool) error {
+	// Get main repo root before any deletion (needed for running git commands after worktree removal)
+	mainRoot, err := git.MainRepoRoot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get main repository root: %w", err)
+	}
+
+	base, err := git.LoadConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	warnUntrustedRepoConfig(base)
+	cfg := branchConfig(cmd, base, "")
+
+	// Check if current directory is one of the worktrees being deleted
+	currentWt, err := git.CurrentWorktree(ctx)
+	if err != nil {
+		currentWt = "" // Not in a worktree, continue
+	}
+
+	hookOpts, err := cfg.HookOptions()
+	if err != nil {
+		return err
+	}
+
+	// Open the trash up front: its operations must not depend on the current
+	// directory, which may itself be moved to the trash.
+	var trash *git.Trash
+	if cfg.Trash {
+		if _, err := git.ParseExpiry(cfg.TrashExpiry); err != nil {
+			return fmt.Errorf("invalid wt.trashexpiry: %w", err)
+		}
+		trash, err = git.OpenTrash(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to open trash: %w", err)
+		}
+	}
+
+	// Planning pass: resolve and validate every target before touching
+	// anything, so that 'git wt -d a b c' either deletes all of them or none
+	// (unless --keep-going is given).
+	results := make([]deleteResult, len(branches))
+	var targets []*deleteTarget
+	var resultIdx []int
+	seen := make(map[string]bool)
+	for i, branch := range branches {
+		results[i].query = branch
+		t, err := planDelete(ctx, branch, force, currentWt)
+		if err != nil {
+			if !keepGoingFlag && !dryRunFlag {
+				return err
+			}
+			results[i].result = "skipped"
+			results[i].err = err
+			continue
+		}
+		// Different arguments may resolve to the same target
+		key := "branch:" + t.branch
+		if t.wt != nil {
+			key = "worktree:" + t.wt.Path
+		}
+		if seen[key] {
+			results[i].result = "duplicate"
+			continue
+		}
+		seen[key] = true
+		// Delete hooks can be scoped to the branch (wt "<pattern>".deletehook)
+		if t.wt != nil {
+			wtBranch := t.wt.Branch
+			if wtBranch == git.DetachedMarker {
+				wtBranch = ""
+			}
+			t.deleteHooks = branchConfig(cmd, base, wtBranch).DeleteHooks
+		}
+		targets = append(targets, t)
+		resultIdx = append(resultIdx, i)
+	}
+
+	if dryRunFlag {
+		return printDeleteDryRun(ctx, cfg, results, targets, resultIdx)
+	}
+
+	// Delete the current worktree last: git cannot run from a removed
+	// working directory.
+	order := make([]int, 0, len(targets))
+	for i, t := range targets {
+		if !t.isCurrent {
+			order = append(order, i)
+		}
+	}
+	for i, t := range targets {
+		if t.isCurrent {
+			order = append(order, i)
+		}
+	}
+
+	var needCdToMain bool
+	for _, i := range order {
+		t := targets[i]
+		res, err := executeDelete(ctx, cfg, hookOpts, t, force, trash, mainRoot, currentWt)
+		if t.isCurrent && (err == nil || res != "") {
+			needCdToMain = true
+		}
+		if err != nil {
+			if !keepGoingFlag {
+				return err
+			}
+			results[resultIdx[i]].result = "failed"
+			results[resultIdx[i]].err = err
+			continue
+		}
+		results[resultIdx[i]].result = res
+	}
+
+	// Purge expired trash entries. Failures do not undo the deletions.
+	if trash != nil {
+		if err := purgeExpiredTrash(ctx, trash, cfg.TrashExpiry); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+
+	var failed int
+	if keepGoingFlag {
+		for _, r := range results {
+			if r.err != nil {
+				failed++
+			}
+		}
+		if err := printDeleteResults(results); err != nil {
+			return err
+		}
+	}
+
+	// If we deleted the current worktree, print main repo path for shell integration to cd
+	// Only output if shell integration is active (GIT_WT_SHELL_INTEGRATION=1)
+	if needCdToMain && os.Getenv("GIT_WT_SHELL_INTEGRATION") == "1" {
+		fmt.Println(mainRoot)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d targets could not be deleted", failed, len(results))
+	}
+	return nil
+}
+
+// printDeleteDryRun prints what deleteWorktrees would do with each argument,
+// including the ones that would be skipped, and returns an error if any would.
+func printDeleteDryRun(ctx context.Context, cfg git.Config, results []deleteResult, targets []*deleteTarget, resultIdx []int) error {
+	plans := make([]deletePlan, len(results))
+	for i, r := range results {
+		plans[i] = deletePlan{Target: r.query, Hooks: []string{}}
+		switch {
+		case r.err != nil:
+			plans[i].Skip = r.err.Error()
+		case r.result == "duplicate":
+			plans[i].Skip = "duplicate of an earlier target"
+		}
+	}
+	for i, t := range targets {
+		p, err := newDeletePlan(ctx, cfg, t)
+		if err != nil {
+			return err
+		}
+		plans[resultIdx[i]] = p
+	}
+	if err := printDeletePlans(os.Stdout, plans); err != nil {
+		return err
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d targets could not be deleted", failed, len(results))
+	}
+	return nil
+}
+
+// planDelete resolves query to a worktree or branch and runs every check of
+// -d (or -D when force is true) without modifying anything.
+func planDelete(ctx context.Context, query string, force bool, currentWt string) (*deleteTarget, error) {
+	// Find worktree by branch or directory name
+	wt, err := git.FindWorktreeByBranchOrDir(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find worktree: %w", err)
+	}
+
+	// Case 1: Worktree exists - remove worktree and optionally branch
+	if wt != nil {
+		t := &deleteTarget{
+			query:     query,
+			wt:        wt,
+			branch:    wt.Branch,
+			isCurrent: currentWt != "" && wt.Path == currentWt,
+		}
+
+		// Get worktree directory name before removal
+		t.wtDir, err = git.WorktreeDirName(ctx, wt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get worktree directory name: %w", err)
+		}
+
+		// Check branch existence and default branch status before removal
+		t.branchExists, err = git.LocalBranchExists(ctx, wt.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check branch existence: %w", err)
+		}
+		if t.branchExists {
+			t.isDefault, err = git.IsDefaultBranch(ctx, wt.Branch)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check default branch: %w", err)
+			}
+		}
+
+		// Safe delete also removes branches squash- or rebase-merged into
+		// the default branch, which 'git branch -d' does not recognize.
+		t.forceBranch = force
+		if t.branchExists && !force && !t.isDefault {
+			t.forceBranch = mergedIntoDefault(ctx, wt.Branch)
+		}
+
+		if force {
+			return t, nil
+		}
+
+		// Locked worktrees are refused by safe delete
+		if wt.Locked {
+			return nil, lockedWorktreeError(query, wt, "-D")
+		}
+
+		// Check for modified or untracked files (only for safe delete)
+		modifiedFiles, err := git.ListModifiedFiles(ctx, wt.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for modified files: %w", err)
+		}
+		if len(modifiedFiles) > 0 {
+			return nil, fmt.Errorf("worktree %q has modified files, use -D to force deletion", query)
+		}
+
+		untrackedFiles, err := git.ListUntrackedFiles(ctx, wt.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for untracked files: %w", err)
+		}
+		if len(untrackedFiles) > 0 {
+			return nil, fmt.Errorf("worktree %q has untracked files, use -D to force deletion", query)
+		}
+
+		// Commits missing from the upstream would be lost with the branch
+		if t.branchExists && (!t.isDefault || allowDeleteDefault) {
+			if err := checkUnpushedCommits(ctx, wt.Branch); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	}
+
+	// Check if the target matches the bare repository entry
+	isBareEntry, err := git.IsBareEntry(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bare entry: %w", err)
+	}
+	if isBareEntry {
+		return nil, fmt.Errorf("cannot delete bare repository entry %q: the bare repository root cannot be removed as a worktree", query)
+	}
+
+	// Case 2: No worktree - try to delete branch only
+	exists, err := git.LocalBranchExists(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check branch existence: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("no worktree or branch found for %q", query)
+	}
+
+	// Check if this is the default branch (protected when no worktree exists)
+	isDefault, err := git.IsDefaultBranch(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check default branch: %w", err)
+	}
+	if isDefault && !allowDeleteDefault {
+		return nil, fmt.Errorf("cannot delete default branch %q: use --allow-delete-default to override", query)
+	}
+
+	t := &deleteTarget{
+		query:        query,
+		branch:       query,
+		branchExists: true,
+		isDefault:    isDefault,
+		forceBranch:  force || mergedIntoDefault(ctx, query),
+	}
+	if force {
+		return t, nil
+	}
+
+	if err := checkUnpushedCommits(ctx, query); err != nil {
+		return nil, err
+	}
+
+	// Mirror the check of 'git branch -d': the branch must be merged into
+	// its upstream (guaranteed by the unpushed check above) or into HEAD.
+	if !t.forceBranch {
+		merged, err := git.IsBranchMergedInto(ctx, query, "HEAD")
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if branch %q is merged: %w", query, err)
+		}
+		if !merged {
+			upstream, err := git.IsBranchMergedInto(ctx, query, query+"@{upstream}")
+			if err != nil || !upstream {
+				return nil, fmt.Errorf("branch %q is not fully merged, use -D to force deletion", query)
+			}
+		}
+	}
+	return t, nil
+}
+
+// executeDelete deletes a target planned by planDelete and returns a short
+// description of what was done. A non-empty description is returned together
+// with an error when the worktree was removed but a later step failed.
+// currentWt is the worktree git-wt was run from (exported to hooks).
+func executeDelete(ctx context.Context, cfg git.Config, hookOpts git.HookOptions, t *deleteTarget, force bool, trash *git.Trash, mainRoot, currentWt string) (string, error) {
+	// Case 2: No worktree - delete branch only
+	if t.wt == nil {
+		if err := git.DeleteBranch(ctx, t.branch, t.forceBranch); err != nil {
+			return "", fmt.Errorf("failed to delete branch (use -D to force): %w", err)
+		}
+		fmt.Printf("Deleted branch %q (no worktree was associated)\n", t.branch)
+		return "deleted branch", nil
+	}
+
+	wt, wtDir, branch := t.wt, t.wtDir, t.query
+	env := git.HookEnv{
+		Action:     git.HookActionDelete,
+		Path:       wt.Path,
+		SourcePath: currentWt,
+		MainRoot:   mainRoot,
+	}
+	if wt.Branch != git.DetachedMarker {
+		env.Branch = wt.Branch
+	}
+
+	// Run delete hooks before worktree removal (directory still exists).
+	// There is nothing to roll back yet, so the rollback policy aborts.
+	if err := git.RunHooks(ctx, t.deleteHooks, wt.Path, env, hookOpts, os.Stderr); err != nil {
+		return "", fmt.Errorf("delete hook failed for worktree %q: %w", branch, err)
+	}
+
+	// Unlock before removal (only reachable with -D). Both 'git worktree
+	// remove' and the prune run after a custom remover refuse locked
+	// worktrees.
+	if wt.Locked {
+		if err := git.UnlockWorktree(ctx, wt.Path); err != nil {
+			return "", fmt.Errorf("failed to unlock worktree %q: %w", branch, err)
+		}
+	}
+
+	// The background hook status is found through the worktree, so look it
+	// up while the worktree still exists.
+	jobDir, jerr := git.HookJobDir(ctx, wt.Path)
+	if jerr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to remove background hook status of %q: %v\n", branch, jerr)
+	}
+
+	// Save uncommitted changes right before they are removed with the
+	// worktree, then remove it. If that fails, the worktree is kept as it was.
+	var stashed, removed string
+	var err error
+	if cfg.DeleteStash {
+		stashed, err = stashWorktreeChanges(ctx, wt, wtDir)
+		if err != nil {
+			err = fmt.Errorf("failed to stash changes of worktree %q: %w", branch, err)
+		}
+	}
+	if err == nil {
+		removed, err = removeDeleteTarget(ctx, cfg, wt, wtDir, branch, force, trash, mainRoot, env)
+	}
+	if removed == "" {
+		if stashed != "" {
+			if serr := unstashWorktreeChanges(ctx, wt, stashed); serr != nil {
+				err = errors.Join(err, serr)
+			}
+		}
+		if wt.Locked {
+			if lerr := git.LockWorktree(ctx, wt.Path, wt.LockReason); lerr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to lock worktree %q again: %w", branch, lerr))
+			}
+		}
+		return "", err
+	}
+
+	// Forget background hooks of the worktree now that it is gone
+	if jobDir != "" {
+		if herr := git.RemoveHookJob(jobDir); herr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to remove background hook status of %q: %v\n", branch, herr)
+		}
+	}
+	if err != nil {
+		return removed, err
+	}
+
+	// Delete branch (only if it exists as a local branch)
+	// Let git branch -d/-D handle the merge check
+	// If we deleted the current worktree, run git from mainRoot since cwd no longer exists.
+	if !t.branchExists {
+		fmt.Printf("Deleted worktree %q (branch %q did not exist locally)\n", wtDir, wt.Branch)
+		return removed, nil
+	}
+	dir := ""
+	if t.isCurrent {
+		dir = mainRoot
+	}
+	if t.isDefault && !allowDeleteDefault {
+		// Default branch is protected - only delete worktree
+		if wtDir == wt.Branch {
+			fmt.Printf("Deleted worktree %q (branch is default, not deleted)\n", wt.Branch)
+		} else {
+			fmt.Printf("Deleted worktree %q (branch %q is default, not deleted)\n", wtDir, wt.Branch)
+		}
+		return removed + " (default branch kept)", nil
+	}
+	if err := git.DeleteBranchInDir(ctx, wt.Branch, t.forceBranch, dir); err != nil {
+		// Treat as non-fatal since worktree removal succeeded
+		if wtDir == wt.Branch {
+			fmt.Printf("Deleted worktree, but failed to delete branch %q (use -D to force)\n", wt.Branch)
+		} else {
+			fmt.Printf("Deleted worktree %q, but failed to delete branch %q (use -D to force)\n", wtDir, wt.Branch)
+		}
+		return removed + " (branch kept: not fully merged)", nil
+	}
+	if wtDir == wt.Branch {
+		fmt.Printf("Deleted worktree and branch %q\n", wt.Branch)
+	} else {
+		fmt.Printf("Deleted worktree %q and branch %q\n", wtDir, wt.Branch)
+	}
+	return removed + " and branch", nil
+}
+
+// removeDeleteTarget removes the worktree of a delete target by moving it to
+// the trash, running the custom remover or 'git worktree remove', and
+// returns a short description of what was done. The description is empty if
+// the worktree was not removed.
+func removeDeleteTarget(ctx context.Context, cfg git.Config, wt *git.Worktree, wtDir, branch string, force bool, trash *git.Trash, mainRoot string, env git.HookEnv) (string, error) {
+	if trash != nil {
+		if _, err := trash.Add(ctx, wt, wtDir); err != nil {
+			return "", fmt.Errorf("failed to move worktree %q to trash: %w", branch, err)
+		}
+		fmt.Fprintf(os.Stderr, "Moved worktree %q to trash (restore with 'git wt --restore %s')\n", wtDir, wtDir)
+		return "moved worktree to trash", nil
+	}
+	removed := "deleted worktree"
+	if cfg.Remover != "" {
+		if err := git.RunRemover(ctx, cfg.Remover, wt.Path, mainRoot, env, os.Stderr); err != nil {
+			return "", fmt.Errorf("remover failed for worktree %q: %w", branch, err)
+		}
+		if err := git.PruneWorktrees(ctx); err != nil {
+			return removed, fmt.Errorf("git worktree prune failed after remover for %q: %w", branch, err)
+		}
+		return removed, nil
+	}
+	if err := git.RemoveWorktree(ctx, wt.Path, force); err != nil {
+		return "", fmt.Errorf("failed to remove worktree: %w", err)
+	}
+	return removed, nil
+}
+
+// printDeleteResults prints the per-target outcome of --keep-going to stderr.
+// Stdout is left alone so that shell integration can still cd.
+func printDeleteResults(results []deleteResult) error {
+	table := newTable(os.Stderr, []string{"TARGET", "RESULT", "DETAIL"})
+	for _, r := range results {
+		detail := ""
+		if r.err != nil {
+			// Keep multi-line errors (e.g., unpushed commits) on one row
+			detail, _, _ = strings.Cut(r.err.Error(), "\n")
+		}
+		if err := table.Append([]string{r.query, r.result, detail}); err != nil {
+			return fmt.Errorf("failed to append row: %w", err)
+		}
+	}
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	return nil
+}
+
+// maxListedUnpushedCommits is the number of commits named in the error
+// returned by checkUnpushedCommits.
+const maxListedUnpushedCommits = 10
+
+// checkUnpushedCommits returns an error naming the commits on branch that are
+// not on its upstream.
+func checkUnpushedCommits(ctx context.Context, branch string) error {
+	commits, err := git.UnpushedCommits(ctx, branch)
+	if err != nil {
+		return fmt.Errorf("failed to check for unpushed commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "branch %q has %d unpushed commit(s) that would be lost:\n", branch, len(commits))
+	for i, c := range commits {
+		if i == maxListedUnpushedCommits {
+			fmt.Fprintf(&b, "  ... and %d more\n", len(commits)-i)
+			break
+		}
+		fmt.Fprintf(&b, "  %s\n", c)
+	}
+	b.WriteString("push them or use -D to force deletion")
+	return errors.New(b.String())
+}
+
+// mergedIntoDefault reports whether branch is merged into the default branch,
+// including squash and rebase merges. Errors (e.g., the default branch cannot
+// be resolved) are treated as not merged so that 'git branch -d' decides.
+func mergedIntoDefault(ctx context.Context, branch string) bool {
+	merged, err := git.IsBranchMerged(ctx, branch)
+	return err == nil && merged
+}
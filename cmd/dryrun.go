@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/k1LoW/git-wt/internal/git"
+)
+
+// createPlan is what creating or switching to a worktree would do, as printed
+// by --dry-run.
+type createPlan struct {
+	Action         string   `json:"action"` // "create" or "switch"
+	Path           string   `json:"path"`
+	Branch         string   `json:"branch"`
+	CreateBranch   bool     `json:"create_branch"`
+	StartPoint     string   `json:"start_point,omitempty"`
+	CopySource     string   `json:"copy_source,omitempty"`
+	Copy           []string `json:"copy"`
+	Symlink        []string `json:"symlink"`
+	Hardlink       []string `json:"hardlink"`
+	PreCreateHooks []string `json:"precreate_hooks"`
+	Hooks          []string `json:"hooks"`
+}
+
+// deletePlan is what -d/-D would do with one target, as printed by --dry-run.
+type deletePlan struct {
+	Target       string   `json:"target"`
+	Worktree     string   `json:"worktree,omitempty"`
+	Branch       string   `json:"branch,omitempty"` // Empty when there is no local branch
+	Current      bool     `json:"current"`
+	DeleteBranch bool     `json:"delete_branch"`
+	ForceBranch  bool     `json:"force_branch"`
+	Stash        bool     `json:"stash"`
+	Trash        bool     `json:"trash"`
+	Remover      string   `json:"remover,omitempty"`
+	Hooks        []string `json:"hooks"`
+	Skip         string   `json:"skip,omitempty"` // Why the target would not be deleted
+}
+
+// movePlan is what -m/-M would do, as printed by --dry-run.
+type movePlan struct {
+	OldPath       string   `json:"old_path"`
+	NewPath       string   `json:"new_path"`
+	OldBranch     string   `json:"old_branch"`
+	NewBranch     string   `json:"new_branch"`
+	MoveDirectory bool     `json:"move_directory"`
+	RenameBranch  bool     `json:"rename_branch"`
+	Hooks         []string `json:"hooks"`
+}
+
+// newDeletePlan describes what executeDelete would do with t.
+func newDeletePlan(ctx context.Context, cfg git.Config, t *deleteTarget) (deletePlan, error) {
+	p := deletePlan{
+		Target:       t.query,
+		DeleteBranch: t.branchExists && (!t.isDefault || allowDeleteDefault),
+		ForceBranch:  t.forceBranch,
+		Hooks:        []string{},
+	}
+	if t.branchExists {
+		p.Branch = t.branch
+	}
+	if t.wt == nil {
+		return p, nil
+	}
+	p.Worktree = t.wt.Path
+	p.Current = t.isCurrent
+	p.Trash = cfg.Trash
+	if !cfg.Trash {
+		p.Remover = cfg.Remover
+	}
+	p.Hooks = append(p.Hooks, t.deleteHooks...)
+	if cfg.DeleteStash {
+		st, err := git.GetWorktreeStatus(ctx, t.wt.Path)
+		if err != nil {
+			return p, fmt.Errorf("failed to get worktree status: %w", err)
+		}
+		p.Stash = st.Dirty()
+	}
+	return p, nil
+}
+
+// Text plans are printed to stdout like regular output, but no line is a bare
+// path so that the shell wrapper never cds.
+
+func printCreatePlan(w io.Writer, p *createPlan) error {
+	if jsonFlag {
+		return encodeJSON(w, p)
+	}
+	if p.Action == "switch" {
+		fmt.Fprintf(w, "Would switch to existing worktree at %s\n", p.Path)
+		for _, hook := range p.Hooks {
+			fmt.Fprintf(w, "  switchhook: %s\n", hook)
+		}
+		return nil
+	}
+	fmt.Fprintf(w, "Would create worktree at %s\n", p.Path)
+	switch {
+	case !p.CreateBranch:
+		fmt.Fprintf(w, "  branch: %s (existing)\n", p.Branch)
+	case p.StartPoint != "":
+		fmt.Fprintf(w, "  branch: %s (new, from %s)\n", p.Branch, p.StartPoint)
+	default:
+		fmt.Fprintf(w, "  branch: %s (new, from HEAD)\n", p.Branch)
+	}
+	for _, hook := range p.PreCreateHooks {
+		fmt.Fprintf(w, "  precreatehook: %s\n", hook)
+	}
+	if p.CopySource != "" && (len(p.Copy) > 0 || len(p.Symlink) > 0 || len(p.Hardlink) > 0) {
+		fmt.Fprintf(w, "  copy from: %s\n", p.CopySource)
+	}
+	for _, dir := range p.Symlink {
+		fmt.Fprintf(w, "  symlink: %s\n", dir)
+	}
+	for _, file := range p.Hardlink {
+		fmt.Fprintf(w, "  hardlink: %s\n", file)
+	}
+	for _, file := range p.Copy {
+		fmt.Fprintf(w, "  copy: %s\n", file)
+	}
+	for _, hook := range p.Hooks {
+		fmt.Fprintf(w, "  hook: %s\n", hook)
+	}
+	return nil
+}
+
+func printDeletePlans(w io.Writer, plans []deletePlan) error {
+	if jsonFlag {
+		return encodeJSON(w, plans)
+	}
+	for _, p := range plans {
+		if p.Skip != "" {
+			// Keep multi-line errors (e.g., unpushed commits) on one line
+			reason, _, _ := strings.Cut(p.Skip, "\n")
+			fmt.Fprintf(w, "Would skip %q: %s\n", p.Target, reason)
+			continue
+		}
+		if p.Worktree == "" {
+			fmt.Fprintf(w, "Would delete branch %q (no worktree)\n", p.Branch)
+			continue
+		}
+		switch {
+		case p.Trash:
+			fmt.Fprintf(w, "Would move worktree %q to trash\n", p.Target)
+		case p.Remover != "":
+			fmt.Fprintf(w, "Would remove worktree %q with %q\n", p.Target, p.Remover)
+		default:
+			fmt.Fprintf(w, "Would remove worktree %q\n", p.Target)
+		}
+		fmt.Fprintf(w, "  path: %s\n", p.Worktree)
+		if p.Current {
+			fmt.Fprintf(w, "  current worktree (deleted last)\n")
+		}
+		if p.Stash {
+			fmt.Fprintf(w, "  stash: uncommitted changes\n")
+		}
+		for _, hook := range p.Hooks {
+			fmt.Fprintf(w, "  deletehook: %s\n", hook)
+		}
+		switch {
+		case p.Branch == "":
+		case !p.DeleteBranch:
+			fmt.Fprintf(w, "  branch: %s (kept)\n", p.Branch)
+		case p.ForceBranch:
+			fmt.Fprintf(w, "  branch: %s (deleted)\n", p.Branch)
+		default:
+			fmt.Fprintf(w, "  branch: %s (deleted if fully merged)\n", p.Branch)
+		}
+	}
+	return nil
+}
+
+func printMovePlan(w io.Writer, p *movePlan) error {
+	if jsonFlag {
+		return encodeJSON(w, p)
+	}
+	if p.MoveDirectory {
+		fmt.Fprintf(w, "Would move worktree from %s to %s\n", p.OldPath, p.NewPath)
+	}
+	if p.RenameBranch {
+		fmt.Fprintf(w, "Would rename branch %q to %q\n", p.OldBranch, p.NewBranch)
+	}
+	for _, hook := range p.Hooks {
+		fmt.Fprintf(w, "  movehook: %s\n", hook)
+	}
+	return nil
+}
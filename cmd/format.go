@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/k1LoW/git-wt/internal/git"
+)
+
+// worktreeFormat is the data passed to the --format template for each worktree.
+// It exposes the fields of git.Worktree (Path, Branch, Head, Bare) and
+// git.WorktreeStatus (Modified, Untracked, Upstream, Ahead, Behind) together
+// with values computed by the list command.
+type worktreeFormat struct {
+	git.Worktree
+	git.WorktreeStatus
+	Dir     string // Directory name relative to the basedir, empty if outside of it
+	Current bool   // True if this is the current worktree
+}
+
+// printFormat writes one line per worktree by evaluating the Go text/template
+// given in format.
+func printFormat(w io.Writer, format string, worktrees []git.Worktree, statuses []*git.WorktreeStatus, currentPath, baseDir string) error {
+	tmpl, err := template.New("format").Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid --format template: %w", err)
+	}
+	for i, wt := range worktrees {
+		data := worktreeFormat{
+			Worktree: wt,
+			Current:  wt.Path == currentPath,
+		}
+		if st := statuses[i]; st != nil {
+			data.WorktreeStatus = *st
+		}
+		if !wt.Bare {
+			if rel, err := filepath.Rel(baseDir, wt.Path); err == nil && !strings.HasPrefix(rel, "..") {
+				data.Dir = rel
+			}
+		}
+		if err := tmpl.Execute(w, data); err != nil {
+			return fmt.Errorf("failed to execute --format template: %w", err)
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
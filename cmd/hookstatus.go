@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/k1LoW/git-wt/internal/git"
+)
+
+// hookStatus reports the background hooks of a worktree, or lists the
+// background hooks of all worktrees without arguments.
+func hookStatus(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return listHookJobs(ctx)
+	}
+
+	query := args[0]
+	wt, err := git.FindWorktreeByBranchOrDir(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to find worktree: %w", err)
+	}
+	if wt == nil {
+		return fmt.Errorf("no worktree found for %q", query)
+	}
+	job, err := git.FindHookJob(ctx, wt.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read background hook status: %w", err)
+	}
+	if job == nil {
+		return fmt.Errorf("no background hooks have run for %q", query)
+	}
+
+	if followFlag {
+		// Stream the log, then report the final status. The status line
+		// comes last so that the shell wrapper never cds to a path printed
+		// by a hook.
+		if err := job.FollowLog(ctx, os.Stdout); err != nil {
+			return fmt.Errorf("failed to follow hook log: %w", err)
+		}
+		fmt.Printf("Background hooks for %q %s\n", query, job.Status)
+		if job.Status == git.HookJobFailed {
+			return fmt.Errorf("%s", job.Error)
+		}
+		return nil
+	}
+
+	if jsonFlag {
+		return encodeJSON(os.Stdout, job)
+	}
+	fmt.Printf("Background hooks for %q: %s\n", query, job.Status)
+	for _, hook := range job.Hooks {
+		fmt.Printf("  hook: %s\n", hook)
+	}
+	fmt.Printf("  started: %s\n", job.StartedAt.Local().Format(time.DateTime))
+	if !job.FinishedAt.IsZero() {
+		fmt.Printf("  finished: %s\n", job.FinishedAt.Local().Format(time.DateTime))
+	}
+	if job.Error != "" {
+		fmt.Printf("  error: %s\n", job.Error)
+	}
+	fmt.Printf("  log: %s\n", job.Log)
+	return nil
+}
+
+// listHookJobs prints the latest background hook job of every worktree,
+// newest first.
+func listHookJobs(ctx context.Context) error {
+	jobs, err := git.ListHookJobs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list background hooks: %w", err)
+	}
+	if jsonFlag {
+		if jobs == nil {
+			jobs = []*git.HookJob{}
+		}
+		return encodeJSON(os.Stdout, jobs)
+	}
+	table := newTable(os.Stdout, []string{"PATH", "BRANCH", "STATUS", "STARTED", "LOG"})
+	for _, job := range jobs {
+		branch := job.Env.Branch
+		if branch == "" {
+			branch = git.DetachedMarker
+		}
+		if err := table.Append([]string{job.Env.Path, branch, job.Status, job.StartedAt.Local().Format(time.DateTime), job.Log}); err != nil {
+			return fmt.Errorf("failed to append row: %w", err)
+		}
+	}
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	return nil
+}
@@ -2,31 +2,79 @@ package cmd
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 
 	"github.com/k1LoW/git-wt/internal/git"
 )
 
 type worktreeJSON struct {
-	Path    string `json:"path"`
-	Branch  string `json:"branch"`
-	Head    string `json:"head"`
-	Bare    bool   `json:"bare"`
-	Current bool   `json:"current"`
+	Path           string `json:"path"`
+	Branch         string `json:"branch"`
+	Head           string `json:"head"`
+	Bare           bool   `json:"bare"`
+	Current        bool   `json:"current"`
+	Locked         bool   `json:"locked"`
+	LockReason     string `json:"lock_reason,omitempty"`
+	Prunable       bool   `json:"prunable"`
+	PrunableReason string `json:"prunable_reason,omitempty"`
+	Modified       int    `json:"modified"`
+	Untracked      int    `json:"untracked"`
+	Upstream       string `json:"upstream"`
+	Ahead          int    `json:"ahead"`
+	Behind         int    `json:"behind"`
 }
 
-func printJSON(w io.Writer, worktrees []git.Worktree, currentPath string) error {
+func printJSON(w io.Writer, worktrees []git.Worktree, statuses []*git.WorktreeStatus, currentPath string) error {
 	items := make([]worktreeJSON, len(worktrees))
 	for i, wt := range worktrees {
 		items[i] = worktreeJSON{
-			Path:    wt.Path,
-			Branch:  wt.Branch,
-			Head:    wt.Head,
-			Bare:    wt.Bare,
-			Current: wt.Path == currentPath,
+			Path:           wt.Path,
+			Branch:         wt.Branch,
+			Head:           wt.Head,
+			Bare:           wt.Bare,
+			Current:        wt.Path == currentPath,
+			Locked:         wt.Locked,
+			LockReason:     wt.LockReason,
+			Prunable:       wt.Prunable,
+			PrunableReason: wt.PrunableReason,
+		}
+		if st := statuses[i]; st != nil {
+			items[i].Modified = st.Modified
+			items[i].Untracked = st.Untracked
+			items[i].Upstream = st.Upstream
+			items[i].Ahead = st.Ahead
+			items[i].Behind = st.Behind
 		}
 	}
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(items)
 }
+
+// worktreeResultJSON is printed instead of the worktree path when creating or
+// switching to a worktree with --json.
+type worktreeResultJSON struct {
+	Action string          `json:"action"` // "create" or "switch"
+	Path   string          `json:"path"`
+	Branch string          `json:"branch"`
+	Copy   *git.CopyResult `json:"copy,omitempty"` // Only when creating
+}
+
+// printWorktreeResult prints the path of the worktree that was created or
+// switched to, or r as JSON with --json. JSON output never ends with a bare
+// path, so the shell wrapper does not cd.
+func printWorktreeResult(w io.Writer, r *worktreeResultJSON) error {
+	if jsonFlag {
+		return encodeJSON(w, r)
+	}
+	_, err := fmt.Fprintln(w, r.Path)
+	return err
+}
+
+// encodeJSON writes v as indented JSON.
+func encodeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/k1LoW/git-wt/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// pruneCandidate is a linked worktree considered by --prune-merged.
+type pruneCandidate struct {
+	wt         git.Worktree
+	skipReason string // Non-empty if the worktree is kept
+}
+
+// pruneMergedWorktrees deletes every linked worktree whose branch is merged
+// into the default branch. Dirty, locked and current worktrees, worktrees
+// with unpushed commits or no commits of their own, and the default branch
+// are skipped. Deletion goes
+// through deleteWorktrees so that delete hooks and the remover apply.
+func pruneMergedWorktrees(ctx context.Context, cmd *cobra.Command) error {
+	candidates, err := findMergedWorktrees(ctx)
+	if err != nil {
+		return err
+	}
+
+	var targets []string
+	for _, c := range candidates {
+		if c.skipReason != "" {
+			fmt.Fprintf(os.Stderr, "Skipping %q (%s)\n", c.wt.Branch, c.skipReason)
+			continue
+		}
+		targets = append(targets, c.wt.Branch)
+	}
+	if len(targets) == 0 {
+		fmt.Fprintln(os.Stderr, "No merged worktrees to delete")
+		return nil
+	}
+
+	verb := "Deleting"
+	if dryRunFlag {
+		verb = "Would delete"
+	}
+	fmt.Fprintf(os.Stderr, "%s %d merged worktree(s):\n", verb, len(targets))
+	for _, c := range candidates {
+		if c.skipReason == "" {
+			fmt.Fprintf(os.Stderr, "  %s\t%s\n", c.wt.Branch, c.wt.Path)
+		}
+	}
+
+	return deleteWorktrees(ctx, cmd, targets, false)
+}
+
+// findMergedWorktrees returns the linked worktrees whose branch is merged
+// (including squash and rebase merges) into the default branch, with a skip reason set for those that must be kept.
+func findMergedWorktrees(ctx context.Context) ([]pruneCandidate, error) {
+	defaultBranch, err := git.DefaultBranch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default branch: %w", err)
+	}
+	target, err := git.DefaultBranchRef(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve default branch: %w", err)
+	}
+	worktrees, err := git.ListWorktrees(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	mainRoot, err := git.MainRepoRoot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get main repository root: %w", err)
+	}
+	currentWt, err := git.CurrentWorktree(ctx)
+	if err != nil {
+		currentWt = "" // Not in a worktree (e.g., bare root)
+	}
+
+	var candidates []pruneCandidate
+	for _, wt := range worktrees {
+		if wt.Bare || sameDir(wt.Path, mainRoot) {
+			continue
+		}
+		if wt.Branch == "" || wt.Branch == git.DetachedMarker || wt.Branch == defaultBranch {
+			continue
+		}
+		merged, err := git.IsBranchMergedInto(ctx, wt.Branch, target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if branch %q is merged: %w", wt.Branch, err)
+		}
+		if !merged {
+			continue
+		}
+
+		c := pruneCandidate{wt: wt}
+		switch {
+		case currentWt != "" && sameDir(wt.Path, currentWt):
+			c.skipReason = "current worktree"
+		case wt.Locked:
+			c.skipReason = "locked"
+		case wt.Prunable:
+			c.skipReason = "worktree directory is missing"
+		default:
+			st, err := git.GetWorktreeStatus(ctx, wt.Path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get status of worktree %q: %w", wt.Branch, err)
+			}
+			if st.Dirty() {
+				c.skipReason = "has modified or untracked files"
+				break
+			}
+			// A branch that was just created from the default branch is
+			// "merged" too, but its worktree is about to be worked in.
+			hasCommits, err := git.BranchHasOwnCommits(ctx, wt.Branch, target)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check for commits on %q: %w", wt.Branch, err)
+			}
+			if !hasCommits {
+				c.skipReason = "no commits since it was created"
+				break
+			}
+			unpushed, err := git.UnpushedCommits(ctx, wt.Branch)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check for unpushed commits on %q: %w", wt.Branch, err)
+			}
+			if len(unpushed) > 0 {
+				c.skipReason = fmt.Sprintf("has %d unpushed commit(s)", len(unpushed))
+			}
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, nil
+}
+
+// sameDir reports whether a and b refer to the same directory after
+// resolving symlinks (e.g., macOS /var -> /private/var).
+func sameDir(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/git-wt/internal/git"
+)
+
+// trustRepoConfig approves the current contents of the .git-wt file of the
+// current worktree.
+func trustRepoConfig(ctx context.Context) error {
+	file, err := git.RepoConfigPath(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find %s: %w", git.RepoConfigFile, err)
+	}
+	if file == "" {
+		return fmt.Errorf("no %s file in the current worktree", git.RepoConfigFile)
+	}
+	if err := git.TrustRepoConfig(ctx, file); err != nil {
+		return fmt.Errorf("failed to trust %s: %w", file, err)
+	}
+	fmt.Fprintf(os.Stderr, "Trusted %s\n", file)
+	return nil
+}
+
+// warnUntrustedRepoConfig tells the user how to enable a .git-wt file that
+// was ignored because its contents have not been approved.
+func warnUntrustedRepoConfig(cfg git.Config) {
+	if cfg.UntrustedRepoConfig == "" {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "warning: ignoring %s because it is not trusted (review it, then run 'git wt --trust')\n", cfg.UntrustedRepoConfig)
+}
@@ -23,10 +23,14 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	"github.com/k1LoW/git-wt/internal/git"
 	"github.com/k1LoW/git-wt/version"
@@ -36,27 +40,54 @@ import (
 )
 
 var (
-	deleteFlag      bool
-	forceDeleteFlag bool
-	moveFlag        bool
-	forceMoveFlag   bool
-	initShell       string
-	nocd            bool
-	branchFlag      string
+	deleteFlag       bool
+	forceDeleteFlag  bool
+	moveFlag         bool
+	forceMoveFlag    bool
+	lockFlag         bool
+	unlockFlag       bool
+	reasonFlag       string
+	pruneMergedFlag  bool
+	keepGoingFlag    bool
+	restoreStashFlag bool
+	restoreFlag      bool
+	purgeTrashFlag   bool
+	dryRunFlag       bool
+	hookStatusFlag   bool
+	followFlag       bool
+	trustFlag        bool
+	hookJobFlag      string
+	initShell        string
+	nocd             bool
+	branchFlag       string
 	// Config override flags.
-	basedirFlag       string
-	copyignoredFlag   bool
-	copyuntrackedFlag bool
-	copymodifiedFlag  bool
-	nocopyFlag        []string
+	basedirFlag        string
+	copyignoredFlag    bool
+	copyuntrackedFlag  bool
+	copymodifiedFlag   bool
+	nocopyFlag         []string
 	copyFlag           []string
 	symlinkFlag        []string
+	hardlinkFlag       []string
+	copyModeFlag       string
+	copyJobsFlag       int
+	copyStrictFlag     bool
+	copyFromFlag       string
 	hookFlag           []string
 	deleteHookFlag     []string
+	preCreateHookFlag  []string
+	switchHookFlag     []string
+	moveHookFlag       []string
+	stashFlag          bool
+	trashFlag          bool
+	rollbackFlag       bool
+	hookTimeoutFlag    string
+	hookFailureFlag    string
 	removerFlag        string
 	allowDeleteDefault bool
 	relativeFlag       bool
 	jsonFlag           bool
+	formatFlag         string
 )
 
 var rootCmd = &cobra.Command{
@@ -66,6 +97,7 @@ var rootCmd = &cobra.Command{
 
 Examples:
   git wt                                         List all worktrees
+  git wt --format '{{.Branch}}'                  List all worktrees using a Go template
   git wt <branch|worktree|path>                  Switch to worktree (create worktree/branch if needed)
   git wt <branch|worktree|path> <start-point>    Create worktree from start-point (e.g., origin/main)
   git wt -b <branch> <worktree>                  Create worktree with a different branch name
@@ -73,12 +105,50 @@ Examples:
   git wt -D <branch|worktree|path>...            Force delete worktree and branch
   git wt -m [<old>] <new>                        Rename worktree directory and branch (safe)
   git wt -M [<old>] <new>                        Force rename (overwrite existing branch, allow moving dirty/locked worktrees)
+  git wt --lock [--reason <reason>] <branch|worktree|path>...
+                                                 Lock worktree (protect from -d, -m and 'git worktree prune')
+  git wt --unlock <branch|worktree|path>...      Unlock worktree
+  git wt --prune-merged                          Delete worktrees whose branch is merged into the default branch
+  git wt --restore-stash [<branch>]              Restore changes saved by --stash into a new worktree (list without <branch>)
+  git wt --restore [<name>]                      Restore a worktree deleted with --trash (list the trash without <name>)
+  git wt --purge-trash [<name>...]               Permanently delete trash entries (expired ones without <name>)
+  git wt --dry-run [--json] ...                  Print what create, -d/-D, -m/-M or --prune-merged would do
+  git wt --hook-status [--follow] [<branch|worktree>]
+                                                 Show the status of background hooks (all worktrees without <branch|worktree>)
+  git wt --trust                                 Trust the committed .git-wt file of the current worktree
 
 Note: The default branch (e.g., main, master) is protected from accidental deletion or rename.
       Pass --allow-delete-default to override the protection in any of the cases below.
       - With worktree: -d removes the worktree but keeps the branch by default; -m/-M refuses to rename by default.
       - Without worktree: deletion is refused by default.
 
+Note: With multiple targets, -d/-D validates all of them before deleting any.
+      --keep-going deletes what it can and prints a per-target result table.
+
+Note: Locked worktrees are refused by -d and -m. -D and -M remove or move them anyway.
+
+Note: -d also deletes branches squash- or rebase-merged into the default branch,
+      and refuses branches with commits missing from their upstream.
+
+Note: --prune-merged skips the default branch, the current worktree, locked or dirty worktrees,
+      worktrees with unpushed commits, and branches with no commits since they were created.
+
+Note: With --json, creating or switching prints the worktree and a summary of the copied
+      files as JSON instead of the path (the shell integration does not cd).
+
+Note: --dry-run resolves paths, branches, start-points, files to copy or symlink, hooks and
+      the remover, and prints the plan (as JSON with --json) without changing anything.
+
+List Format:
+  --format takes a Go text/template evaluated once per worktree. Available fields:
+    .Path .Branch .Head .Bare                   Worktree entry
+    .Locked .LockReason .Prunable .PrunableReason
+                                                Lock and prune state
+    .Dir                                        Directory name relative to wt.basedir (empty if outside)
+    .Current                                    true for the current worktree
+    .Modified .Untracked .Upstream .Ahead .Behind .Dirty
+                                                Working tree and upstream status
+
 Shell Integration:
   Add the following to your shell config to enable worktree switching and completion:
 
@@ -136,13 +206,62 @@ Configuration:
     Can be specified multiple times.
     Example: git config --add wt.symlink "node_modules/"
 
+  wt.hardlink (--hardlink)
+    Patterns for files to hard link instead of copy (gitignore syntax).
+    Linked files take no extra disk space but share their contents with the
+    source worktree. Files are copied, with a warning, across filesystems.
+    Can be specified multiple times.
+    Example: git config --add wt.hardlink "models/"
+
+  wt.copyfrom (--copy-from)
+    Worktree to copy files from: a branch name, worktree directory name or
+    path, or "default" for the default branch's worktree. Also lets you copy
+    files when running from the root of a bare repository.
+    Default: the current worktree
+    Example: git config wt.copyfrom default
+
+  wt.copymode (--copymode)
+    How copied files are written: auto (clone with copy-on-write where the
+    filesystem supports it, e.g. APFS, Btrfs or XFS, and copy otherwise),
+    reflink (only clone; other files fail to copy) or copy (always copy).
+    Default: auto
+    Example: git config wt.copymode reflink
+
+  wt.copyjobs (--copyjobs)
+    Number of files copied or hard linked in parallel.
+    Default: number of CPUs
+    Example: git config wt.copyjobs 16
+
+  wt.copystrict (--copystrict)
+    Fail the creation if any file cannot be copied or linked instead of
+    printing a warning (with wt.rollback, the new worktree is removed).
+    A summary of the copied files is printed either way.
+    Default: false
+    Example: git config wt.copystrict true
+
   wt.hook (--hook)
     Commands to run after creating a new worktree.
     Can be specified multiple times. Hooks run in the new worktree directory.
-    Note: Hooks do NOT run when switching to an existing worktree.
+    Note: Hooks do NOT run when switching to an existing worktree (see wt.switchhook).
+    Environment: GIT_WT_ACTION (create, switch, delete, move), GIT_WT_BRANCH,
+                 GIT_WT_PATH, GIT_WT_SOURCE_PATH, GIT_WT_MAIN_ROOT, GIT_WT_START_POINT
+                 and GIT_WT_OLD_PATH are set for all hooks and the remover.
     Example: git config --add wt.hook "npm install"
              git config --add wt.hook "go generate ./..."
 
+  wt.rollback (--rollback)
+    Remove a new worktree when copying files or a hook fails, delete its branch
+    if git-wt created it, and clean up directories left empty under the basedir.
+    Interrupting a creation (Ctrl-C or SIGTERM) always rolls it back.
+    Default: false
+    Example: git config wt.rollback true
+
+  wt.precreatehook (--precreatehook)
+    Commands to run before creating a new worktree, in the main repository root.
+    Can be specified multiple times. A non-zero exit aborts the creation with
+    the hook's stderr as the error (e.g., to enforce branch naming policies).
+    Example: git config --add wt.precreatehook 'case "$GIT_WT_BRANCH" in ABC-*) ;; *) exit 1;; esac'
+
   wt.deletehook (--deletehook)
     Commands to run before deleting a worktree.
     Can be specified multiple times. Hooks run in the worktree directory
@@ -150,6 +269,35 @@ Configuration:
     Note: Hooks do NOT run when deleting a branch without a worktree.
     Example: git config --add wt.deletehook "git push origin --delete $(git branch --show-current)"
 
+  wt.switchhook (--switchhook)
+    Commands to run after switching to an existing worktree.
+    Can be specified multiple times. Hooks run in the worktree directory.
+    Example: git config --add wt.switchhook 'tmux rename-window "$GIT_WT_BRANCH"'
+
+  wt.movehook (--movehook)
+    Commands to run after renaming a worktree with -m/-M.
+    Can be specified multiple times. Hooks run in the renamed worktree directory.
+    Example: git config --add wt.movehook 'tmux rename-window "$GIT_WT_BRANCH"'
+
+  wt.hooktimeout (--hooktimeout)
+    Kill hooks that run longer than this duration, including commands they
+    started in the background (their whole process group).
+    Default: (not set, no timeout)
+    Example: git config wt.hooktimeout 5m
+
+  wt.hookfailure (--hookfailure)
+    What to do when a hook fails or times out: abort (stop with an error),
+    warn (print a warning and continue) or rollback (abort and roll back a
+    newly created worktree, as with wt.rollback). Pre-create hooks always veto.
+    Both settings can be overridden per hook with an option prefix.
+    Default: abort
+    Example: git config wt.hookfailure warn
+             git config --add wt.hook "[timeout=10m,failure=warn] npm ci"
+
+    Hooks with the async option ("[async] npm ci") run in the background after
+    the other hooks, logging to <git common dir>/wt-hooks/<worktree>/hooks.log.
+    Check them with --hook-status. Delete hooks always run synchronously.
+
   wt.remover (--remover)
     Custom command to remove the worktree directory instead of 'git worktree remove'.
     The worktree path is passed as an argument to the command.
@@ -174,7 +322,24 @@ Configuration:
     subdirectory relative to the repository root (like git diff --relative).
     Falls back to worktree root if the subdirectory does not exist in the worktree.
     Default: false
-    Example: git config wt.relative true`,
+    Example: git config wt.relative true
+
+  Branch-scoped sections (wt "<pattern>")
+    hook, precreatehook, switchhook, movehook, deletehook, copy, nocopy,
+    symlink and hardlink values in a wt "<pattern>" section are added to the
+    global ones for branches matching the pattern ("*" stops at "/", "**" and
+    a trailing "/" match across it, as in includeIf "onbranch:").
+    Example: git config --add 'wt.frontend/*.hook' "npm ci"
+             git config --add 'wt.backend/*.copy' ".env"
+
+  Shared configuration (.git-wt)
+    A .git-wt file committed at the repository root (git config syntax) can set
+    the same keys, including wt "<pattern>" sections, for the whole team. Its
+    values come before the ones from git config. Since it runs commands from
+    the repository, it is ignored until trusted with --trust; the SHA-256 is
+    recorded in wt.trusted of the global git config and a changed file must
+    be trusted again.
+    Example: git wt --trust`,
 	RunE:              runRoot,
 	Args:              cobra.ArbitraryArgs,
 	ValidArgsFunction: completeBranches,
@@ -183,7 +348,16 @@ Configuration:
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Cancel the context on SIGINT/SIGTERM so that create and move can undo
+	// a partially completed step. A second signal terminates immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		os.Exit(1)
 	}
 }
@@ -199,6 +373,20 @@ func init() {
 	rootCmd.Flags().BoolVarP(&forceDeleteFlag, "force-delete", "D", false, "Force delete worktree and branch by name or path")
 	rootCmd.Flags().BoolVarP(&moveFlag, "move", "m", false, "Rename worktree directory and branch (safe rename)")
 	rootCmd.Flags().BoolVarP(&forceMoveFlag, "force-move", "M", false, "Force rename worktree directory and branch (allow overwriting existing branch and moving dirty/locked worktrees)")
+	rootCmd.Flags().BoolVar(&lockFlag, "lock", false, "Lock worktree by name or path (protect from -d, -m and 'git worktree prune')")
+	rootCmd.Flags().BoolVar(&unlockFlag, "unlock", false, "Unlock worktree by name or path")
+	rootCmd.Flags().StringVar(&reasonFlag, "reason", "", "Reason recorded with --lock")
+	rootCmd.Flags().BoolVar(&keepGoingFlag, "keep-going", false, "With -d/-D/--prune-merged, delete what can be deleted and print a per-target result table instead of stopping at the first error")
+	rootCmd.Flags().BoolVar(&pruneMergedFlag, "prune-merged", false, "Delete linked worktrees whose branch is merged into the default branch (skips dirty, locked and current worktrees)")
+	rootCmd.Flags().BoolVar(&restoreStashFlag, "restore-stash", false, "Restore changes saved by --stash into a new worktree for the branch (lists saved stashes without arguments)")
+	rootCmd.Flags().BoolVar(&restoreFlag, "restore", false, "Restore a worktree deleted with --trash (lists the trash without arguments)")
+	rootCmd.Flags().BoolVar(&purgeTrashFlag, "purge-trash", false, "Permanently delete the named trash entries (expired entries without arguments)")
+	rootCmd.Flags().BoolVar(&hookStatusFlag, "hook-status", false, "Show the status of background (async) hooks of a worktree (all worktrees without arguments)")
+	rootCmd.Flags().BoolVar(&followFlag, "follow", false, "With --hook-status <worktree>, print the hook log until the background hooks finish")
+	rootCmd.Flags().StringVar(&hookJobFlag, git.HookJobRunnerFlag, "", "Run a background hook job (internal)")
+	_ = rootCmd.Flags().MarkHidden(git.HookJobRunnerFlag) //nostyle:handlerrors
+	rootCmd.Flags().BoolVar(&trustFlag, "trust", false, "Trust the current contents of the committed .git-wt config file, allowing its hooks to run")
+	rootCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Print what creating, deleting or moving worktrees would do without changing anything (text, or JSON with --json)")
 	rootCmd.Flags().StringVar(&initShell, "init", "", "Output shell initialization script (bash, zsh, fish, powershell)")
 	rootCmd.Flags().BoolVar(&nocd, "nocd", false, "Do not change directory to the worktree (also disables git() wrapper when used with --init)")
 	rootCmd.Flags().BoolVar(&nocd, "no-switch-directory", false, "")
@@ -214,12 +402,26 @@ func init() {
 	rootCmd.Flags().StringArrayVar(&nocopyFlag, "nocopy", nil, "Exclude files matching pattern from copying (can be specified multiple times)")
 	rootCmd.Flags().StringArrayVar(&copyFlag, "copy", nil, "Always copy files matching pattern (can be specified multiple times)")
 	rootCmd.Flags().StringArrayVar(&symlinkFlag, "symlink", nil, "Symlink directories matching pattern instead of copying (can be specified multiple times)")
+	rootCmd.Flags().StringArrayVar(&hardlinkFlag, "hardlink", nil, "Hard link files matching pattern instead of copying (can be specified multiple times)")
+	rootCmd.Flags().StringVar(&copyModeFlag, "copymode", "", "Override wt.copymode config (auto, reflink or copy)")
+	rootCmd.Flags().StringVar(&copyFromFlag, "copy-from", "", "Override wt.copyfrom config (copy files from this worktree, or from the default branch's worktree with 'default')")
+	rootCmd.Flags().BoolVar(&copyStrictFlag, "copystrict", false, "Override wt.copystrict config (fail the creation if any file cannot be copied)")
+	rootCmd.Flags().IntVar(&copyJobsFlag, "copyjobs", 0, "Override wt.copyjobs config (number of files copied in parallel)")
 	rootCmd.Flags().StringArrayVar(&hookFlag, "hook", nil, "Run command after creating new worktree (can be specified multiple times)")
+	rootCmd.Flags().StringArrayVar(&preCreateHookFlag, "precreatehook", nil, "Run command before creating a worktree; a non-zero exit aborts the creation (can be specified multiple times)")
 	rootCmd.Flags().StringArrayVar(&deleteHookFlag, "deletehook", nil, "Run command before deleting a worktree (can be specified multiple times)")
+	rootCmd.Flags().StringArrayVar(&switchHookFlag, "switchhook", nil, "Run command after switching to an existing worktree (can be specified multiple times)")
+	rootCmd.Flags().StringArrayVar(&moveHookFlag, "movehook", nil, "Run command after renaming a worktree with -m/-M (can be specified multiple times)")
+	rootCmd.Flags().BoolVar(&stashFlag, "stash", false, "Override wt.deletestash config (save uncommitted changes of deleted worktrees to refs/wt-stash/<branch>)")
+	rootCmd.Flags().BoolVar(&trashFlag, "trash", false, "Override wt.trash config (move deleted worktrees to a trash under the git directory instead of removing them)")
+	rootCmd.Flags().BoolVar(&rollbackFlag, "rollback", false, "Override wt.rollback config (remove the new worktree and branch if copying files or a hook fails)")
+	rootCmd.Flags().StringVar(&hookTimeoutFlag, "hooktimeout", "", "Override wt.hooktimeout config (kill hooks running longer than this, e.g., 5m)")
+	rootCmd.Flags().StringVar(&hookFailureFlag, "hookfailure", "", "Override wt.hookfailure config (what to do when a hook fails: abort, warn or rollback)")
 	rootCmd.Flags().StringVar(&removerFlag, "remover", "", "Custom command to remove worktree directory (e.g., trash-put)")
 	rootCmd.Flags().BoolVar(&allowDeleteDefault, "allow-delete-default", false, "Allow deletion of the default branch (main, master)")
 	rootCmd.Flags().BoolVar(&relativeFlag, "relative", false, "Append current subdirectory to worktree path (like git diff --relative)")
 	rootCmd.Flags().BoolVar(&jsonFlag, "json", false, "Output in JSON format")
+	rootCmd.Flags().StringVar(&formatFlag, "format", "", "Format each worktree in the list using a Go template (e.g., '{{.Branch}}')")
 }
 
 func runRoot(cmd *cobra.Command, args []string) error {
@@ -230,6 +432,11 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		return runInit(initShell, nocd)
 	}
 
+	// Detached runner of async hooks started by RunHooks
+	if hookJobFlag != "" {
+		return git.RunHookJob(ctx, hookJobFlag)
+	}
+
 	// Detect repo context once and thread it through context.
 	// Subsequent calls to DetectRepoContext will reuse the cached value
 	// instead of spawning git processes again.
@@ -239,9 +446,123 @@ func runRoot(cmd *cobra.Command, args []string) error {
 	}
 	ctx = git.WithRepoContext(ctx, rc)
 
+	if cmd.Flags().Changed("reason") && !lockFlag {
+		return fmt.Errorf("--reason can only be used with --lock")
+	}
+
+	if keepGoingFlag && !deleteFlag && !forceDeleteFlag && !pruneMergedFlag {
+		return fmt.Errorf("--keep-going can only be used with -d/-D/--prune-merged")
+	}
+
+	if cmd.Flags().Changed("stash") && !deleteFlag && !forceDeleteFlag && !pruneMergedFlag {
+		return fmt.Errorf("--stash can only be used with -d/-D/--prune-merged")
+	}
+
+	if cmd.Flags().Changed("trash") && !deleteFlag && !forceDeleteFlag && !pruneMergedFlag {
+		return fmt.Errorf("--trash can only be used with -d/-D/--prune-merged")
+	}
+
+	if followFlag && !hookStatusFlag {
+		return fmt.Errorf("--follow can only be used with --hook-status")
+	}
+
+	if dryRunFlag && (lockFlag || unlockFlag || restoreFlag || purgeTrashFlag || restoreStashFlag || hookStatusFlag || trustFlag || len(args) == 0 && !pruneMergedFlag) {
+		return fmt.Errorf("--dry-run can only be used when creating, deleting or moving worktrees")
+	}
+
+	// Handle trust flag (no arguments)
+	if trustFlag {
+		if len(args) > 0 {
+			return fmt.Errorf("--trust does not take arguments")
+		}
+		if deleteFlag || forceDeleteFlag || moveFlag || forceMoveFlag || lockFlag || unlockFlag || pruneMergedFlag || restoreStashFlag || restoreFlag || purgeTrashFlag || hookStatusFlag || branchFlag != "" {
+			return fmt.Errorf("cannot combine --trust with other commands")
+		}
+		return trustRepoConfig(ctx)
+	}
+
+	// Handle hook-status flag (at most one argument)
+	if hookStatusFlag {
+		if deleteFlag || forceDeleteFlag || moveFlag || forceMoveFlag || lockFlag || unlockFlag || pruneMergedFlag || restoreStashFlag || restoreFlag || purgeTrashFlag {
+			return fmt.Errorf("cannot combine --hook-status with -d/-D/-m/-M/--lock/--unlock/--prune-merged/--restore-stash/--restore/--purge-trash")
+		}
+		if branchFlag != "" {
+			return fmt.Errorf("cannot use -b/--branch with --hook-status")
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("too many arguments: expected [<branch|worktree>] for --hook-status, got %d arguments", len(args))
+		}
+		if followFlag && len(args) == 0 {
+			return fmt.Errorf("--follow requires a <branch|worktree>")
+		}
+		return hookStatus(ctx, args)
+	}
+
+	// Handle trash flags
+	if restoreFlag || purgeTrashFlag {
+		if restoreFlag && purgeTrashFlag {
+			return fmt.Errorf("cannot combine --restore with --purge-trash")
+		}
+		if deleteFlag || forceDeleteFlag || moveFlag || forceMoveFlag || lockFlag || unlockFlag || pruneMergedFlag || restoreStashFlag {
+			return fmt.Errorf("cannot combine --restore/--purge-trash with -d/-D/-m/-M/--lock/--unlock/--prune-merged/--restore-stash")
+		}
+		if branchFlag != "" {
+			return fmt.Errorf("cannot use -b/--branch with --restore/--purge-trash")
+		}
+		if purgeTrashFlag {
+			return purgeTrash(ctx, cmd, uniqueArgs(args))
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("too many arguments: expected [<name>] for --restore, got %d arguments", len(args))
+		}
+		return restoreTrash(ctx, cmd, args)
+	}
+
+	// Handle restore-stash flag (at most one argument)
+	if restoreStashFlag {
+		if deleteFlag || forceDeleteFlag || moveFlag || forceMoveFlag || lockFlag || unlockFlag || pruneMergedFlag {
+			return fmt.Errorf("cannot combine --restore-stash with -d/-D/-m/-M/--lock/--unlock/--prune-merged")
+		}
+		if branchFlag != "" {
+			return fmt.Errorf("cannot use -b/--branch with --restore-stash")
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("too many arguments: expected [<branch>] for --restore-stash, got %d arguments", len(args))
+		}
+		return restoreStash(ctx, cmd, args)
+	}
+
+	// Handle lock/unlock flags (multiple arguments allowed)
+	if lockFlag || unlockFlag {
+		if lockFlag && unlockFlag {
+			return fmt.Errorf("cannot combine --lock with --unlock")
+		}
+		if deleteFlag || forceDeleteFlag || moveFlag || forceMoveFlag || pruneMergedFlag {
+			return fmt.Errorf("cannot combine --lock/--unlock with -d/-D/-m/-M/--prune-merged")
+		}
+		if branchFlag != "" {
+			return fmt.Errorf("cannot use -b/--branch with --lock/--unlock")
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("expected at least one <branch|worktree|path> for --lock/--unlock")
+		}
+		return lockWorktrees(ctx, uniqueArgs(args), lockFlag)
+	}
+
+	// Handle prune-merged flag (no arguments)
+	if pruneMergedFlag {
+		if len(args) > 0 {
+			return fmt.Errorf("--prune-merged does not take arguments")
+		}
+		if deleteFlag || forceDeleteFlag || moveFlag || forceMoveFlag || branchFlag != "" {
+			return fmt.Errorf("cannot combine --prune-merged with -d/-D/-m/-M/-b")
+		}
+		return pruneMergedWorktrees(ctx, cmd)
+	}
+
 	// No arguments: list worktrees
 	if len(args) == 0 {
-		return listWorktrees(ctx)
+		return listWorktrees(ctx, cmd)
 	}
 
 	// Handle delete flags (multiple arguments allowed)
@@ -292,15 +613,30 @@ func runRoot(cmd *cobra.Command, args []string) error {
 	}
 
 	// Default: create or switch to worktree
-	return handleWorktree(ctx, cmd, wtName, branchName, startPoint)
+	return handleWorktree(ctx, cmd, wtName, branchName, startPoint, nil)
 }
 
 // loadConfig loads config from git config and applies flag overrides.
 func loadConfig(ctx context.Context, cmd *cobra.Command) (git.Config, error) {
+	return loadBranchConfig(ctx, cmd, "")
+}
+
+// loadBranchConfig is loadConfig for an operation on branch: the
+// wt "<pattern>" sections matching branch are merged in before flag
+// overrides.
+func loadBranchConfig(ctx context.Context, cmd *cobra.Command, branch string) (git.Config, error) {
 	cfg, err := git.LoadConfig(ctx)
 	if err != nil {
 		return cfg, err
 	}
+	warnUntrustedRepoConfig(cfg)
+	return branchConfig(cmd, cfg, branch), nil
+}
+
+// branchConfig merges the wt "<pattern>" sections of cfg matching branch
+// and applies flag overrides. Flags replace scoped values too.
+func branchConfig(cmd *cobra.Command, cfg git.Config, branch string) git.Config {
+	cfg = cfg.ForBranch(branch)
 
 	// Apply flag overrides
 	if cmd.Flags().Changed("basedir") {
@@ -324,27 +660,66 @@ func loadConfig(ctx context.Context, cmd *cobra.Command) (git.Config, error) {
 	if cmd.Flags().Changed("symlink") {
 		cfg.Symlink = symlinkFlag
 	}
+	if cmd.Flags().Changed("hardlink") {
+		cfg.Hardlink = hardlinkFlag
+	}
+	if cmd.Flags().Changed("copymode") {
+		cfg.CopyMode = copyModeFlag
+	}
+	if cmd.Flags().Changed("copyjobs") {
+		cfg.CopyJobs = copyJobsFlag
+	}
+	if cmd.Flags().Changed("copystrict") {
+		cfg.CopyStrict = copyStrictFlag
+	}
+	if cmd.Flags().Changed("copy-from") {
+		cfg.CopyFrom = copyFromFlag
+	}
 	if cmd.Flags().Changed("hook") {
 		cfg.Hooks = hookFlag
 	}
 	if cmd.Flags().Changed("deletehook") {
 		cfg.DeleteHooks = deleteHookFlag
 	}
+	if cmd.Flags().Changed("precreatehook") {
+		cfg.PreCreateHooks = preCreateHookFlag
+	}
+	if cmd.Flags().Changed("switchhook") {
+		cfg.SwitchHooks = switchHookFlag
+	}
+	if cmd.Flags().Changed("movehook") {
+		cfg.MoveHooks = moveHookFlag
+	}
 	if cmd.Flags().Changed("remover") {
 		cfg.Remover = removerFlag
 	}
+	if cmd.Flags().Changed("stash") {
+		cfg.DeleteStash = stashFlag
+	}
+	if cmd.Flags().Changed("trash") {
+		cfg.Trash = trashFlag
+	}
 	if cmd.Flags().Changed("relative") {
 		cfg.Relative = relativeFlag
 	}
+	if cmd.Flags().Changed("rollback") {
+		cfg.Rollback = rollbackFlag
+	}
+	if cmd.Flags().Changed("hooktimeout") {
+		cfg.HookTimeout = hookTimeoutFlag
+	}
+	if cmd.Flags().Changed("hookfailure") {
+		cfg.HookFailure = hookFailureFlag
+	}
 
-	return cfg, nil
+	return cfg
 }
 
 func completeBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	ctx := cmd.Context()
 
 	// For second argument (start-point), complete with branches including remote
-	if len(args) == 1 && !deleteFlag && !forceDeleteFlag && !moveFlag && !forceMoveFlag {
+	if len(args) == 1 && !deleteFlag && !forceDeleteFlag && !moveFlag && !forceMoveFlag && !lockFlag && !unlockFlag {
 		return completeStartPoint(ctx)
 	}
 
@@ -355,7 +730,7 @@ func completeBranches(cmd *cobra.Command, args []string, toComplete string) ([]s
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	// For delete flags, allow multiple arguments (same completion as first arg)
+	// For delete and lock flags, allow multiple arguments (same completion as first arg)
 	// For first argument or delete mode, complete with worktrees and local branches
 
 	// Collect unique branch names and worktree directory names
@@ -520,7 +895,11 @@ func truncateString(s string, maxLen int) string {
 	return string(r[:maxLen-3]) + "..."
 }
 
-func listWorktrees(ctx context.Context) error {
+func listWorktrees(ctx context.Context, cmd *cobra.Command) error {
+	if jsonFlag && formatFlag != "" {
+		return fmt.Errorf("cannot use --format with --json")
+	}
+
 	worktrees, err := git.ListWorktrees(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list worktrees: %w", err)
@@ -531,12 +910,51 @@ func listWorktrees(ctx context.Context) error {
 		return fmt.Errorf("failed to get current location: %w", err)
 	}
 
+	statuses := git.ListWorktreeStatuses(ctx, worktrees)
+
 	if jsonFlag {
-		return printJSON(os.Stdout, worktrees, currentPath)
+		return printJSON(os.Stdout, worktrees, statuses, currentPath)
+	}
+
+	if formatFlag != "" {
+		cfg, err := loadConfig(ctx, cmd)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		baseDir, err := git.ExpandBaseDir(ctx, cfg.BaseDir)
+		if err != nil {
+			return fmt.Errorf("failed to expand basedir: %w", err)
+		}
+		return printFormat(os.Stdout, formatFlag, worktrees, statuses, currentPath, baseDir)
+	}
+
+	table := newTable(os.Stdout, []string{"", "PATH", "BRANCH", "HEAD", "STATUS", "UPSTREAM"})
+
+	for i, wt := range worktrees {
+		marker := ""
+		if wt.Path == currentPath {
+			marker = "*"
+		}
+		branch := wt.Branch
+		if wt.Bare {
+			branch = "(bare)"
+		}
+		if err := table.Append([]string{marker, wt.Path, branch, wt.Head, formatStatus(wt, statuses[i]), formatUpstream(statuses[i])}); err != nil {
+			return fmt.Errorf("failed to append row: %w", err)
+		}
 	}
 
-	table := tablewriter.NewTable(os.Stdout,
-		tablewriter.WithHeader([]string{"", "PATH", "BRANCH", "HEAD"}),
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	return nil
+}
+
+// newTable returns a borderless table with the given header, as used by the
+// worktree list.
+func newTable(w io.Writer, header []string) *tablewriter.Table {
+	return tablewriter.NewTable(w,
+		tablewriter.WithHeader(header),
 		tablewriter.WithHeaderAlignment(tw.AlignLeft),
 		tablewriter.WithHeaderPaddingPerColumn([]tw.Padding{tw.PaddingNone}),
 		tablewriter.WithRowPaddingPerColumn([]tw.Padding{tw.PaddingNone}),
@@ -562,196 +980,101 @@ func listWorktrees(ctx context.Context) error {
 				},
 			},
 		}))
+}
 
-	for _, wt := range worktrees {
-		marker := ""
-		if wt.Path == currentPath {
-			marker = "*"
-		}
-		branch := wt.Branch
-		if wt.Bare {
-			branch = "(bare)"
-		}
-		if err := table.Append([]string{marker, wt.Path, branch, wt.Head}); err != nil {
-			return fmt.Errorf("failed to append row: %w", err)
+// formatStatus returns the STATUS column value for the worktree list.
+// Locked and prunable worktrees are flagged first, then modified files are
+// shown as "~N" and untracked files as "?N".
+func formatStatus(wt git.Worktree, st *git.WorktreeStatus) string {
+	var parts []string
+	if wt.Locked {
+		parts = append(parts, "locked")
+	}
+	if wt.Prunable {
+		parts = append(parts, "prunable")
+	}
+	switch {
+	case st == nil:
+		if len(parts) == 0 {
+			return "-"
 		}
+		return strings.Join(parts, " ")
+	case !st.Dirty():
+		return strings.Join(append(parts, "clean"), " ")
 	}
-
-	if err := table.Render(); err != nil {
-		return fmt.Errorf("failed to render table: %w", err)
+	if st.Modified > 0 {
+		parts = append(parts, fmt.Sprintf("~%d", st.Modified))
 	}
-	return nil
+	if st.Untracked > 0 {
+		parts = append(parts, fmt.Sprintf("?%d", st.Untracked))
+	}
+	return strings.Join(parts, " ")
 }
 
-func deleteWorktrees(ctx context.Context, cmd *cobra.Command, branches []string, force bool) error {
-	// Get main repo root before any deletion (needed for running git commands after worktree removal)
-	mainRoot, err := git.MainRepoRoot(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get main repository root: %w", err)
+// formatUpstream returns the UPSTREAM column value for the worktree list.
+// Commits ahead of and behind the upstream are shown as "↑N" and "↓N".
+func formatUpstream(st *git.WorktreeStatus) string {
+	if st == nil || st.Upstream == "" {
+		return "-"
 	}
-
-	cfg, err := loadConfig(ctx, cmd)
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	parts := []string{st.Upstream}
+	if st.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", st.Ahead))
 	}
-
-	// Check if current directory is one of the worktrees being deleted
-	currentWt, err := git.CurrentWorktree(ctx)
-	if err != nil {
-		currentWt = "" // Not in a worktree, continue
+	if st.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", st.Behind))
 	}
+	return strings.Join(parts, " ")
+}
 
-	var needCdToMain bool
+// lockedWorktreeError returns the error reported when a locked worktree is
+// the target of a safe operation. forceFlag names the flag that overrides
+// the lock (e.g., "-D").
+func lockedWorktreeError(query string, wt *git.Worktree, forceFlag string) error {
+	if wt.LockReason != "" {
+		return fmt.Errorf("worktree %q is locked (reason: %s), run 'git wt --unlock %s' first or use %s to force", query, wt.LockReason, query, forceFlag)
+	}
+	return fmt.Errorf("worktree %q is locked, run 'git wt --unlock %s' first or use %s to force", query, query, forceFlag)
+}
 
-	for _, branch := range branches {
-		// Find worktree by branch or directory name
-		wt, err := git.FindWorktreeByBranchOrDir(ctx, branch)
+// lockWorktrees locks (lock == true) or unlocks each worktree resolved from
+// queries. The reason given with --reason is recorded with the lock.
+func lockWorktrees(ctx context.Context, queries []string, lock bool) error {
+	for _, query := range queries {
+		wt, err := git.FindWorktreeByBranchOrDir(ctx, query)
 		if err != nil {
 			return fmt.Errorf("failed to find worktree: %w", err)
 		}
-
-		// Case 1: Worktree exists - remove worktree and optionally branch
-		if wt != nil {
-			// Check if we're deleting the current worktree
-			if currentWt != "" && wt.Path == currentWt {
-				needCdToMain = true
-			}
-
-			// Get worktree directory name before removal
-			wtDir, err := git.WorktreeDirName(ctx, wt)
+		if wt == nil {
+			isBareEntry, err := git.IsBareEntry(ctx, query)
 			if err != nil {
-				return fmt.Errorf("failed to get worktree directory name: %w", err)
+				return fmt.Errorf("failed to check bare entry: %w", err)
 			}
-
-			// Check branch existence and default branch status before removal
-			branchExists, err := git.LocalBranchExists(ctx, wt.Branch)
-			if err != nil {
-				return fmt.Errorf("failed to check branch existence: %w", err)
-			}
-
-			// Check if this is the default branch (must be done before worktree removal)
-			var isDefault bool
-			if branchExists {
-				isDefault, err = git.IsDefaultBranch(ctx, wt.Branch)
-				if err != nil {
-					return fmt.Errorf("failed to check default branch: %w", err)
-				}
-			}
-
-			// Check for modified or untracked files (only for safe delete)
-			if !force {
-				modifiedFiles, err := git.ListModifiedFiles(ctx, wt.Path)
-				if err != nil {
-					return fmt.Errorf("failed to check for modified files: %w", err)
-				}
-				if len(modifiedFiles) > 0 {
-					return fmt.Errorf("worktree %q has modified files, use -D to force deletion", branch)
-				}
-
-				untrackedFiles, err := git.ListUntrackedFiles(ctx, wt.Path)
-				if err != nil {
-					return fmt.Errorf("failed to check for untracked files: %w", err)
-				}
-				if len(untrackedFiles) > 0 {
-					return fmt.Errorf("worktree %q has untracked files, use -D to force deletion", branch)
-				}
+			if isBareEntry {
+				return fmt.Errorf("cannot lock or unlock bare repository entry %q", query)
 			}
+			return fmt.Errorf("no worktree found for %q", query)
+		}
 
-			// Run delete hooks before worktree removal (directory still exists)
-			if err := git.RunHooks(ctx, cfg.DeleteHooks, wt.Path, os.Stderr); err != nil {
-				return fmt.Errorf("delete hook failed for worktree %q: %w", branch, err)
+		if lock {
+			if wt.Locked {
+				return fmt.Errorf("worktree %q is already locked", query)
 			}
-
-			// Remove worktree
-			if cfg.Remover != "" {
-				if err := git.RunRemover(ctx, cfg.Remover, wt.Path, mainRoot, os.Stderr); err != nil {
-					return fmt.Errorf("remover failed for worktree %q: %w", branch, err)
-				}
-				if err := git.PruneWorktrees(ctx); err != nil {
-					return fmt.Errorf("git worktree prune failed after remover for %q: %w", branch, err)
-				}
-			} else {
-				if err := git.RemoveWorktree(ctx, wt.Path, force); err != nil {
-					return fmt.Errorf("failed to remove worktree: %w", err)
-				}
-			}
-
-			// Delete branch (only if it exists as a local branch)
-			// Let git branch -d/-D handle the merge check
-			// If we deleted the current worktree, run git from mainRoot since cwd no longer exists.
-			// needCdToMain stays true for subsequent iterations as well.
-			if branchExists {
-				dir := ""
-				if needCdToMain {
-					dir = mainRoot
-				}
-				if isDefault && !allowDeleteDefault {
-					// Default branch is protected - only delete worktree
-					if wtDir == wt.Branch {
-						fmt.Printf("Deleted worktree %q (branch is default, not deleted)\n", wt.Branch)
-					} else {
-						fmt.Printf("Deleted worktree %q (branch %q is default, not deleted)\n", wtDir, wt.Branch)
-					}
-				} else if err := git.DeleteBranchInDir(ctx, wt.Branch, force, dir); err != nil {
-					// Treat as non-fatal since worktree removal succeeded
-					if wtDir == wt.Branch {
-						fmt.Printf("Deleted worktree, but failed to delete branch %q (use -D to force)\n", wt.Branch)
-					} else {
-						fmt.Printf("Deleted worktree %q, but failed to delete branch %q (use -D to force)\n", wtDir, wt.Branch)
-					}
-				} else {
-					if wtDir == wt.Branch {
-						fmt.Printf("Deleted worktree and branch %q\n", wt.Branch)
-					} else {
-						fmt.Printf("Deleted worktree %q and branch %q\n", wtDir, wt.Branch)
-					}
-				}
-			} else {
-				fmt.Printf("Deleted worktree %q (branch %q did not exist locally)\n", wtDir, wt.Branch)
+			if err := git.LockWorktree(ctx, wt.Path, reasonFlag); err != nil {
+				return fmt.Errorf("failed to lock worktree %q: %w", query, err)
 			}
+			fmt.Printf("Locked worktree %q\n", query)
 			continue
 		}
 
-		// Check if the target matches the bare repository entry
-		isBareEntry, err := git.IsBareEntry(ctx, branch)
-		if err != nil {
-			return fmt.Errorf("failed to check bare entry: %w", err)
+		if !wt.Locked {
+			return fmt.Errorf("worktree %q is not locked", query)
 		}
-		if isBareEntry {
-			return fmt.Errorf("cannot delete bare repository entry %q: the bare repository root cannot be removed as a worktree", branch)
-		}
-
-		// Case 2: No worktree - try to delete branch only
-		exists, err := git.LocalBranchExists(ctx, branch)
-		if err != nil {
-			return fmt.Errorf("failed to check branch existence: %w", err)
+		if err := git.UnlockWorktree(ctx, wt.Path); err != nil {
+			return fmt.Errorf("failed to unlock worktree %q: %w", query, err)
 		}
-
-		if !exists {
-			return fmt.Errorf("no worktree or branch found for %q", branch)
-		}
-
-		// Check if this is the default branch (protected when no worktree exists)
-		isDefault, err := git.IsDefaultBranch(ctx, branch)
-		if err != nil {
-			return fmt.Errorf("failed to check default branch: %w", err)
-		}
-		if isDefault && !allowDeleteDefault {
-			return fmt.Errorf("cannot delete default branch %q: use --allow-delete-default to override", branch)
-		}
-
-		if err := git.DeleteBranch(ctx, branch, force); err != nil {
-			return fmt.Errorf("failed to delete branch (use -D to force): %w", err)
-		}
-		fmt.Printf("Deleted branch %q (no worktree was associated)\n", branch)
-	}
-
-	// If we deleted the current worktree, print main repo path for shell integration to cd
-	// Only output if shell integration is active (GIT_WT_SHELL_INTEGRATION=1)
-	if needCdToMain && os.Getenv("GIT_WT_SHELL_INTEGRATION") == "1" {
-		fmt.Println(mainRoot)
+		fmt.Printf("Unlocked worktree %q\n", query)
 	}
-
 	return nil
 }
 
@@ -771,10 +1094,14 @@ func moveWorktree(ctx context.Context, cmd *cobra.Command, args []string, force
 		newName = args[1]
 	}
 
-	cfg, err := loadConfig(ctx, cmd)
+	cfg, err := loadBranchConfig(ctx, cmd, newName)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	hookOpts, err := cfg.HookOptions()
+	if err != nil {
+		return err
+	}
 	baseDir, err := git.ExpandBaseDir(ctx, cfg.BaseDir)
 	if err != nil {
 		return fmt.Errorf("failed to expand basedir: %w", err)
@@ -834,6 +1161,15 @@ func moveWorktree(ctx context.Context, cmd *cobra.Command, args []string, force
 		return fmt.Errorf("cannot rename worktree at %q: it has no branch (detached HEAD)", src.Path)
 	}
 
+	// Locked worktrees can only be moved with -M.
+	if !force && src.Locked {
+		query := oldQuery
+		if query == "" {
+			query = src.Branch
+		}
+		return lockedWorktreeError(query, src, "-M")
+	}
+
 	// Reject the main working tree explicitly. The 1-arg form already blocks
 	// this via rc.IsLinkedWorktree(), but the 2-arg form can resolve the
 	// main worktree (e.g. `git wt -m . new`, or by passing its path), so
@@ -930,6 +1266,18 @@ func moveWorktree(ctx context.Context, cmd *cobra.Command, args []string, force
 		}
 	}
 
+	if dryRunFlag {
+		return printMovePlan(os.Stdout, &movePlan{
+			OldPath:       oldPath,
+			NewPath:       newPath,
+			OldBranch:     src.Branch,
+			NewBranch:     newName,
+			MoveDirectory: !samePath,
+			RenameBranch:  src.Branch != newName,
+			Hooks:         append([]string{}, cfg.MoveHooks...),
+		})
+	}
+
 	// Detect whether we are currently inside the worktree being renamed.
 	curWt, _ := git.CurrentWorktree(ctx) //nostyle:handlerrors
 	inside := false
@@ -944,7 +1292,14 @@ func moveWorktree(ctx context.Context, cmd *cobra.Command, args []string, force
 	// identical (only the branch changes).
 	if !samePath {
 		if err := git.MoveWorktree(ctx, oldPath, newPath, force); err != nil {
-			return fmt.Errorf("failed to move worktree: %w", err)
+			err = fmt.Errorf("failed to move worktree: %w", err)
+			if ctx.Err() != nil {
+				err = fmt.Errorf("interrupted: %w", err)
+				if rerr := undoInterruptedMove(context.WithoutCancel(ctx), oldPath, newPath, baseDir); rerr != nil {
+					return errors.Join(err, rerr)
+				}
+			}
+			return err
 		}
 		// Clean up now-empty parent directories under basedir (e.g., the "feat/"
 		// left behind when renaming "feat/foo" out of basedir/feat/foo).
@@ -957,8 +1312,40 @@ func moveWorktree(ctx context.Context, cmd *cobra.Command, args []string, force
 	// Rename the branch. Run from the new worktree path so the command works
 	// even when the old cwd has just been removed.
 	if src.Branch != newName {
-		if err := git.RenameBranch(ctx, src.Branch, newName, force, newPath); err != nil {
-			return fmt.Errorf("failed to rename branch: %w", err)
+		err := ctx.Err()
+		if err == nil {
+			err = git.RenameBranch(ctx, src.Branch, newName, force, newPath)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to rename branch: %w", err)
+			if ctx.Err() != nil && !samePath {
+				// Interrupted between the two steps: move the directory back
+				// so that the worktree and its branch stay consistent.
+				err = fmt.Errorf("interrupted: %w", err)
+				if rerr := undoMoveWorktree(context.WithoutCancel(ctx), oldPath, newPath, src.Branch, baseDir); rerr != nil {
+					return errors.Join(err, rerr)
+				}
+			}
+			return err
+		}
+	}
+
+	// Run move hooks in the renamed worktree
+	if len(cfg.MoveHooks) > 0 {
+		env := git.HookEnv{
+			Action:     git.HookActionMove,
+			Branch:     newName,
+			Path:       newPath,
+			SourcePath: curWt,
+			MainRoot:   mainRoot,
+			OldPath:    oldPath,
+		}
+		if err := git.RunHooks(ctx, cfg.MoveHooks, newPath, env, hookOpts, os.Stderr); err != nil {
+			// Print path but return error so shell integration won't cd
+			if inside && os.Getenv("GIT_WT_SHELL_INTEGRATION") == "1" {
+				fmt.Println(newPath)
+			}
+			return fmt.Errorf("move hook failed for worktree %q (the worktree has been renamed): %w", newName, err)
 		}
 	}
 
@@ -982,12 +1369,39 @@ func moveWorktree(ctx context.Context, cmd *cobra.Command, args []string, force
 	return nil
 }
 
-func handleWorktree(ctx context.Context, cmd *cobra.Command, wtName, branchName, startPoint string) error {
-	// Load config with flag overrides
-	cfg, err := loadConfig(ctx, cmd)
+// handleWorktree switches to the worktree for branchName, creating it (and the
+// branch from startPoint) if needed. beforeHooks, if non-nil, runs on a newly
+// created worktree before the create hooks.
+func handleWorktree(ctx context.Context, cmd *cobra.Command, wtName, branchName, startPoint string, beforeHooks func(wtPath string) error) error {
+	// Check if worktree already exists for this branch or directory name
+	wt, err := git.FindWorktreeByBranchOrDir(ctx, branchName)
+	if err != nil {
+		return fmt.Errorf("failed to find worktree: %w", err)
+	}
+	if wt == nil && branchName != wtName {
+		// Also try finding by worktree directory name
+		wt, err = git.FindWorktreeByBranchOrDir(ctx, wtName)
+		if err != nil {
+			return fmt.Errorf("failed to find worktree: %w", err)
+		}
+	}
+
+	// Load config for the branch of the worktree with flag overrides
+	cfgBranch := branchName
+	if wt != nil {
+		cfgBranch = wt.Branch
+		if cfgBranch == git.DetachedMarker {
+			cfgBranch = ""
+		}
+	}
+	cfg, err := loadBranchConfig(ctx, cmd, cfgBranch)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	hookOpts, err := cfg.HookOptions()
+	if err != nil {
+		return err
+	}
 
 	// Check for legacy basedir migration (only if --basedir flag is not set)
 	if !cmd.Flags().Changed("basedir") {
@@ -1008,28 +1422,53 @@ func handleWorktree(ctx context.Context, cmd *cobra.Command, wtName, branchName,
 		NoCopy:        cfg.NoCopy,
 		Copy:          cfg.Copy,
 		Symlink:       cfg.Symlink,
-	}
-
-	// Check if worktree already exists for this branch or directory name
-	wt, err := git.FindWorktreeByBranchOrDir(ctx, branchName)
-	if err != nil {
-		return fmt.Errorf("failed to find worktree: %w", err)
-	}
-	if wt == nil && branchName != wtName {
-		// Also try finding by worktree directory name
-		wt, err = git.FindWorktreeByBranchOrDir(ctx, wtName)
-		if err != nil {
-			return fmt.Errorf("failed to find worktree: %w", err)
-		}
+		Hardlink:      cfg.Hardlink,
+		Jobs:          cfg.CopyJobs,
+		Mode:          cfg.CopyMode,
+		Strict:        cfg.CopyStrict,
 	}
 
 	if wt != nil {
 		if startPoint != "" {
 			return fmt.Errorf("worktree for branch %q already exists at %s (start-point %q is not allowed when switching to an existing worktree)", wt.Branch, wt.Path, startPoint)
 		}
-		// Worktree exists, switch to it
-		fmt.Println(resolveRelative(ctx, wt.Path, cfg.Relative))
-		return nil
+		if dryRunFlag {
+			return printCreatePlan(os.Stdout, &createPlan{
+				Action:   "switch",
+				Path:     wt.Path,
+				Branch:   wt.Branch,
+				Copy:     []string{},
+				Symlink:  []string{},
+				Hardlink: []string{},
+				Hooks:    append([]string{}, cfg.SwitchHooks...),
+			})
+		}
+		// Worktree exists, run switch hooks and switch to it
+		switched := &worktreeResultJSON{Action: "switch", Branch: wt.Branch}
+		if len(cfg.SwitchHooks) > 0 {
+			env := git.HookEnv{
+				Action: git.HookActionSwitch,
+				Path:   wt.Path,
+			}
+			if wt.Branch != git.DetachedMarker {
+				env.Branch = wt.Branch
+			}
+			env.SourcePath, _ = git.CurrentWorktree(ctx) //nostyle:handlerrors
+			env.MainRoot, err = git.MainRepoRoot(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get main repository root: %w", err)
+			}
+			if err := git.RunHooks(ctx, cfg.SwitchHooks, wt.Path, env, hookOpts, os.Stderr); err != nil {
+				// Print path but return error so shell integration won't cd
+				switched.Path = resolveRelative(ctx, wt.Path, cfg.Relative)
+				if perr := printWorktreeResult(os.Stdout, switched); perr != nil {
+					return errors.Join(err, perr)
+				}
+				return err
+			}
+		}
+		switched.Path = resolveRelative(ctx, wt.Path, cfg.Relative)
+		return printWorktreeResult(os.Stdout, switched)
 	}
 
 	// Get worktree path using the worktree name (not the branch name)
@@ -1048,26 +1487,239 @@ func handleWorktree(ctx context.Context, cmd *cobra.Command, wtName, branchName,
 		if startPoint != "" {
 			return fmt.Errorf("branch %q already exists (start-point %q is not allowed for existing branches)", branchName, startPoint)
 		}
+	}
+
+	copyOpts.Source, err = resolveCopyFrom(ctx, cfg.CopyFrom)
+	if err != nil {
+		return err
+	}
+
+	if dryRunFlag {
+		plan, err := git.PlanAddCopy(ctx, wtPath, copyOpts)
+		if err != nil {
+			return fmt.Errorf("failed to plan file copy: %w", err)
+		}
+		return printCreatePlan(os.Stdout, &createPlan{
+			Action:         "create",
+			Path:           wtPath,
+			Branch:         branchName,
+			CreateBranch:   !exists,
+			StartPoint:     startPoint,
+			CopySource:     plan.Source,
+			Copy:           append([]string{}, plan.Files...),
+			Symlink:        append([]string{}, plan.Symlinks...),
+			Hardlink:       append([]string{}, plan.Hardlinks...),
+			PreCreateHooks: append([]string{}, cfg.PreCreateHooks...),
+			Hooks:          append([]string{}, cfg.Hooks...),
+		})
+	}
+
+	env := git.HookEnv{
+		Action:     git.HookActionCreate,
+		Branch:     branchName,
+		Path:       wtPath,
+		StartPoint: startPoint,
+	}
+	env.SourcePath, _ = git.CurrentWorktree(ctx) //nostyle:handlerrors
+	env.MainRoot, err = git.MainRepoRoot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get main repository root: %w", err)
+	}
+
+	// Run pre-create hooks before anything is created; they can veto the
+	// creation (e.g., to enforce a branch naming policy).
+	if err := git.RunPreHooks(ctx, cfg.PreCreateHooks, env.MainRoot, env, hookOpts, os.Stderr); err != nil {
+		return fmt.Errorf("worktree creation aborted by precreate hook: %w", err)
+	}
+
+	// With wt.rollback, a failed copy or hook step undoes the creation, as
+	// does a hook with the rollback failure policy. An interrupted creation
+	// (Ctrl-C) is always undone. Remember what exists
+	// beforehand so that only what git-wt creates here is removed: git
+	// worktree add also accepts an existing empty directory, which is kept.
+	baseDir, err := git.ExpandBaseDir(ctx, cfg.BaseDir)
+	if err != nil {
+		return fmt.Errorf("failed to expand basedir: %w", err)
+	}
+	rollbackStop := baseDir
+	if _, err := os.Stat(baseDir); os.IsNotExist(err) {
+		rollbackStop = filepath.Dir(baseDir)
+	}
+	_, err = os.Stat(wtPath)
+	pathExisted := err == nil
+	rollback := func(cause error, enabled bool) error {
+		interrupted := ctx.Err() != nil
+		if !enabled && !interrupted {
+			return cause
+		}
+		if interrupted {
+			cause = fmt.Errorf("interrupted: %w", cause)
+		}
+		// The rollback itself must not be cancelled by the interruption
+		rctx := context.WithoutCancel(ctx)
+		// Remove the worktree if git added it, or the directory an
+		// interrupted 'git worktree add' left behind
+		_, err := os.Stat(wtPath)
+		remove := worktreeAdded(rctx, wtPath) || err == nil && !pathExisted
+		if err := rollbackWorktree(rctx, wtPath, remove, branchName, !exists, rollbackStop); err != nil {
+			return errors.Join(cause, fmt.Errorf("rollback failed: %w", err))
+		}
+		if remove && pathExisted {
+			if err := os.MkdirAll(wtPath, 0755); err != nil {
+				return errors.Join(cause, fmt.Errorf("rollback failed: %w", err))
+			}
+		}
+		return cause
+	}
+
+	var copyResult *git.CopyResult
+	if exists {
 		// Branch exists, create worktree with existing branch
-		if err := git.AddWorktree(ctx, wtPath, branchName, copyOpts); err != nil {
-			return fmt.Errorf("failed to create worktree: %w", err)
+		copyResult, err = git.AddWorktree(ctx, wtPath, branchName, copyOpts)
+		printCopySummary(os.Stderr, copyResult)
+		if err != nil {
+			err = fmt.Errorf("failed to create worktree: %w", err)
+			if worktreeAdded(context.WithoutCancel(ctx), wtPath) || ctx.Err() != nil {
+				// The worktree was added but copying files failed, or
+				// 'git worktree add' was interrupted
+				return rollback(err, cfg.Rollback)
+			}
+			return err
 		}
 	} else {
 		// Branch doesn't exist, create new branch and worktree
-		if err := git.AddWorktreeWithNewBranch(ctx, wtPath, branchName, startPoint, copyOpts); err != nil {
-			return fmt.Errorf("failed to create worktree with new branch: %w", err)
+		copyResult, err = git.AddWorktreeWithNewBranch(ctx, wtPath, branchName, startPoint, copyOpts)
+		printCopySummary(os.Stderr, copyResult)
+		if err != nil {
+			err = fmt.Errorf("failed to create worktree with new branch: %w", err)
+			if worktreeAdded(context.WithoutCancel(ctx), wtPath) || ctx.Err() != nil {
+				// The worktree was added but copying files failed, or
+				// 'git worktree add' was interrupted
+				return rollback(err, cfg.Rollback)
+			}
+			return err
+		}
+	}
+	created := &worktreeResultJSON{Action: "create", Branch: branchName, Copy: copyResult}
+
+	if beforeHooks != nil {
+		if err := beforeHooks(wtPath); err != nil {
+			return err
 		}
 	}
 
 	// Run hooks after creating new worktree
-	if err := git.RunHooks(ctx, cfg.Hooks, wtPath, os.Stderr); err != nil {
+	if err := git.RunHooks(ctx, cfg.Hooks, wtPath, env, hookOpts, os.Stderr); err != nil {
+		var herr *git.HookError
+		enabled := cfg.Rollback || errors.As(err, &herr) && herr.Failure == git.HookFailureRollback
+		if enabled || ctx.Err() != nil {
+			return rollback(err, enabled)
+		}
 		// Print path but return error so shell integration won't cd
-		fmt.Println(resolveRelative(ctx, wtPath, cfg.Relative))
+		created.Path = resolveRelative(ctx, wtPath, cfg.Relative)
+		if perr := printWorktreeResult(os.Stdout, created); perr != nil {
+			return errors.Join(err, perr)
+		}
 		return err
 	}
 
 	// Print path to stdout
-	fmt.Println(resolveRelative(ctx, wtPath, cfg.Relative))
+	created.Path = resolveRelative(ctx, wtPath, cfg.Relative)
+	return printWorktreeResult(os.Stdout, created)
+}
+
+// undoMoveWorktree moves a worktree that moveWorktree moved to newPath back
+// to oldPath, unless its branch has already been renamed.
+func undoMoveWorktree(ctx context.Context, oldPath, newPath, oldBranch, baseDir string) error {
+	exists, err := git.LocalBranchExists(ctx, oldBranch)
+	if err != nil {
+		return fmt.Errorf("failed to check branch existence: %w", err)
+	}
+	if !exists {
+		// The branch rename completed; keep the move
+		return nil
+	}
+	if err := git.MoveWorktree(ctx, newPath, oldPath, true); err != nil {
+		return fmt.Errorf("failed to move worktree back to %s: %w", oldPath, err)
+	}
+	if err := git.RemoveEmptyParents(filepath.Dir(newPath), baseDir); err != nil {
+		return fmt.Errorf("failed to clean up empty parent directories: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Moved worktree back to %q\n", oldPath)
+	return nil
+}
+
+// undoInterruptedMove restores a worktree whose 'git worktree move' was
+// interrupted after the directory had been renamed: the directory is moved
+// back and git's administrative files are repaired to point to it again.
+func undoInterruptedMove(ctx context.Context, oldPath, newPath, baseDir string) error {
+	_, oldErr := os.Stat(oldPath)
+	_, newErr := os.Stat(newPath)
+	if oldErr == nil || newErr != nil {
+		// The directory was not moved
+		return nil
+	}
+	if err := os.Rename(newPath, oldPath); err != nil {
+		return fmt.Errorf("failed to move worktree back to %s: %w", oldPath, err)
+	}
+	if err := git.RepairWorktree(ctx, oldPath); err != nil {
+		return fmt.Errorf("failed to repair worktree %s: %w", oldPath, err)
+	}
+	if err := git.RemoveEmptyParents(filepath.Dir(newPath), baseDir); err != nil {
+		return fmt.Errorf("failed to clean up empty parent directories: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Moved worktree back to %q\n", oldPath)
+	return nil
+}
+
+// worktreeAdded reports whether git knows a worktree at path. handleWorktree
+// only creates worktrees at paths git does not know yet, so this tells
+// whether its 'git worktree add' succeeded.
+func worktreeAdded(ctx context.Context, path string) bool {
+	worktrees, err := git.ListWorktrees(ctx)
+	if err != nil {
+		return false
+	}
+	for _, wt := range worktrees {
+		if sameDir(wt.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// rollbackWorktree undoes what handleWorktree could not finish: it removes
+// the worktree at wtPath if removeWorktree is true, deletes the branch if
+// deleteBranch is true and git-wt created it, and removes the directories left
+// empty below stopDir. wtPath must have been created by handleWorktree: if git
+// no longer knows the worktree (e.g., 'git worktree add' was interrupted),
+// the directory is removed as is.
+func rollbackWorktree(ctx context.Context, wtPath string, removeWorktree bool, branch string, deleteBranch bool, stopDir string) error {
+	if removeWorktree {
+		if err := git.RemoveWorktree(ctx, wtPath, true); err != nil {
+			if err := os.RemoveAll(wtPath); err != nil {
+				return fmt.Errorf("failed to remove worktree %s: %w", wtPath, err)
+			}
+			if err := git.PruneWorktrees(ctx); err != nil {
+				return fmt.Errorf("git worktree prune failed: %w", err)
+			}
+		}
+	}
+	if deleteBranch {
+		exists, err := git.LocalBranchExists(ctx, branch)
+		if err != nil {
+			return fmt.Errorf("failed to check branch existence: %w", err)
+		}
+		if exists {
+			if err := git.DeleteBranch(ctx, branch, true); err != nil {
+				return fmt.Errorf("failed to delete branch %q: %w", branch, err)
+			}
+		}
+	}
+	if err := git.RemoveEmptyParents(filepath.Dir(wtPath), stopDir); err != nil {
+		return fmt.Errorf("failed to clean up empty parent directories: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Rolled back worktree %q\n", wtPath)
 	return nil
 }
 
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/git-wt/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// stashKey returns the name under which the changes of wt are stashed: the
+// branch, or the worktree directory name for a detached HEAD.
+func stashKey(wt *git.Worktree, wtDir string) string {
+	if wt.Branch == "" || wt.Branch == git.DetachedMarker {
+		return wtDir
+	}
+	return wt.Branch
+}
+
+// stashWorktreeChanges saves the modified and untracked files of wt (if any)
+// to refs/wt-stash/<branch> so that they can be restored with --restore-stash.
+// It returns the name the changes were saved under, or "" if there were none.
+func stashWorktreeChanges(ctx context.Context, wt *git.Worktree, wtDir string) (string, error) {
+	st, err := git.GetWorktreeStatus(ctx, wt.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree status: %w", err)
+	}
+	if !st.Dirty() {
+		return "", nil
+	}
+	key := stashKey(wt, wtDir)
+	if err := git.SaveWorktreeStash(ctx, wt.Path, key); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(os.Stderr, "Saved uncommitted changes of %q to %s (restore with 'git wt --restore-stash %s')\n", key, git.WorktreeStashRef(key), key)
+	return key, nil
+}
+
+// unstashWorktreeChanges applies the changes stashWorktreeChanges saved under
+// key back to wt, whose removal failed.
+func unstashWorktreeChanges(ctx context.Context, wt *git.Worktree, key string) error {
+	if err := git.ApplyWorktreeStash(ctx, wt.Path, key); err != nil {
+		return fmt.Errorf("failed to restore uncommitted changes (they are kept in %s; run 'git stash apply %s' in %s): %w", git.WorktreeStashRef(key), git.WorktreeStashRef(key), wt.Path, err)
+	}
+	fmt.Fprintf(os.Stderr, "Restored uncommitted changes of %q\n", key)
+	return nil
+}
+
+// restoreStash creates a worktree for the branch and applies the changes saved
+// by --stash. Without arguments it lists the branches that have a saved stash.
+func restoreStash(ctx context.Context, cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		branches, err := git.ListWorktreeStashes(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list stashes: %w", err)
+		}
+		for _, b := range branches {
+			fmt.Println(b)
+		}
+		return nil
+	}
+
+	branch := args[0]
+	base, err := git.WorktreeStashBase(ctx, branch)
+	if err != nil {
+		return err
+	}
+
+	wt, err := git.FindWorktreeByBranchOrDir(ctx, branch)
+	if err != nil {
+		return fmt.Errorf("failed to find worktree: %w", err)
+	}
+	if wt != nil {
+		return fmt.Errorf("worktree for %q already exists at %s (run 'git stash apply %s' there to restore the changes)", branch, wt.Path, git.WorktreeStashRef(branch))
+	}
+
+	// Recreate a deleted branch at the commit the changes were stashed on
+	exists, err := git.BranchExists(ctx, branch)
+	if err != nil {
+		return fmt.Errorf("failed to check branch: %w", err)
+	}
+	var startPoint string
+	if !exists {
+		startPoint = base
+	}
+
+	return handleWorktree(ctx, cmd, branch, branch, startPoint, func(wtPath string) error {
+		if err := git.ApplyWorktreeStash(ctx, wtPath, branch); err != nil {
+			return fmt.Errorf("worktree created at %s, but %w", wtPath, err)
+		}
+		fmt.Fprintf(os.Stderr, "Restored uncommitted changes of %q\n", branch)
+		return nil
+	})
+}
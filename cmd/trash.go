@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/k1LoW/git-wt/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// restoreTrash moves a soft-deleted worktree back from the trash and prints
+// its path. Without arguments it lists the trash.
+func restoreTrash(ctx context.Context, cmd *cobra.Command, args []string) error {
+	trash, err := git.OpenTrash(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open trash: %w", err)
+	}
+
+	if len(args) == 0 {
+		return listTrash(trash)
+	}
+
+	e, err := trash.Find(args[0])
+	if err != nil {
+		return err
+	}
+	if err := trash.Restore(ctx, e); err != nil {
+		return fmt.Errorf("failed to restore %q: %w", e.ID, err)
+	}
+
+	cfg, err := loadConfig(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Restored worktree %q from trash\n", e.ID)
+	fmt.Println(resolveRelative(ctx, e.Path, cfg.Relative))
+	return nil
+}
+
+// listTrash prints the entries in the trash, newest first.
+func listTrash(trash *git.Trash) error {
+	entries, err := trash.List()
+	if err != nil {
+		return fmt.Errorf("failed to list trash: %w", err)
+	}
+	table := newTable(os.Stdout, []string{"NAME", "BRANCH", "PATH", "DELETED"})
+	for _, e := range entries {
+		branch := e.Branch
+		if branch == "" {
+			branch = git.DetachedMarker
+		}
+		if err := table.Append([]string{e.ID, branch, e.Path, e.DeletedAt.Local().Format(time.DateTime)}); err != nil {
+			return fmt.Errorf("failed to append row: %w", err)
+		}
+	}
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	return nil
+}
+
+// purgeTrash permanently deletes the named trash entries, or the expired ones
+// (see wt.trashexpiry) when no names are given.
+func purgeTrash(ctx context.Context, cmd *cobra.Command, args []string) error {
+	trash, err := git.OpenTrash(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open trash: %w", err)
+	}
+
+	if len(args) == 0 {
+		cfg, err := loadConfig(ctx, cmd)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		return purgeExpiredTrash(ctx, trash, cfg.TrashExpiry)
+	}
+
+	for _, name := range args {
+		e, err := trash.Find(name)
+		if err != nil {
+			return err
+		}
+		if err := trash.Purge(ctx, e); err != nil {
+			return fmt.Errorf("failed to purge %q: %w", e.ID, err)
+		}
+		fmt.Fprintf(os.Stderr, "Purged %q from trash\n", e.ID)
+	}
+	return nil
+}
+
+// purgeExpiredTrash purges the trash entries older than expiry.
+func purgeExpiredTrash(ctx context.Context, trash *git.Trash, expiry string) error {
+	d, err := git.ParseExpiry(expiry)
+	if err != nil {
+		return fmt.Errorf("invalid wt.trashexpiry: %w", err)
+	}
+	purged, err := trash.PurgeExpired(ctx, d, time.Now())
+	for _, e := range purged {
+		fmt.Fprintf(os.Stderr, "Purged %q from trash (expired)\n", e.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return nil
+}
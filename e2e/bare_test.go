@@ -3,6 +3,7 @@
 // Covered scenarios:
 //   - List operation: supported in both bare root and worktrees from bare repos
 //   - Add/switch operations: supported in both bare root and worktrees from bare repos
+//   - Copy from the bare root: files are copied when --copy-from names a worktree
 //   - Delete operation: supported from bare-derived worktrees; bare entry itself is protected
 package e2e
 
@@ -312,6 +313,27 @@ func TestE2E_BareRepository(t *testing.T) {
 		}
 	})
 
+	t.Run("bare_root_add_copies_files_with_copy_from", func(t *testing.T) {
+		t.Parallel()
+		bareRepo := testutil.NewBareTestRepo(t)
+
+		wtPath := filepath.Join(bareRepo.ParentDir(), "wt-main")
+		addRawWorktreeFromBare(t, bareRepo.Root, wtPath, "main")
+		if err := os.WriteFile(filepath.Join(wtPath, "untracked.txt"), []byte("test content\n"), 0600); err != nil {
+			t.Fatalf("failed to create untracked.txt: %v", err)
+		}
+
+		// The bare root has no working tree, so the files come from main's worktree
+		stdout, _, err := runGitWtStdout(t, binPath, bareRepo.Root, "--copyuntracked", "--copy-from", "main", "feature-copy")
+		if err != nil {
+			t.Fatalf("expected success, but got error: %v\nstdout: %s", err, stdout)
+		}
+		copiedPath := filepath.Join(worktreePath(stdout), "untracked.txt")
+		if _, err := os.Stat(copiedPath); os.IsNotExist(err) {
+			t.Errorf("untracked.txt should be copied from main's worktree to %s", copiedPath)
+		}
+	})
+
 	t.Run("bare_add_chain", func(t *testing.T) {
 		t.Parallel()
 		bareRepo := testutil.NewBareTestRepo(t)
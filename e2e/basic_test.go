@@ -1,5 +1,5 @@
 // basic_test.go contains basic functionality tests:
-//   - TestE2E_ListWorktrees: listing worktrees and table formatting
+//   - TestE2E_ListWorktrees: listing worktrees, table formatting, status columns and --format
 //   - TestE2E_CreateWorktree: creating worktrees (basic, start-point, existing branch, from worktree)
 //   - TestE2E_SwitchWorktree: switching to existing worktrees
 //   - TestE2E_SwitchWorktreeByPath: switching to worktrees by filesystem path
@@ -175,6 +175,140 @@ func TestE2E_ListWorktrees(t *testing.T) {
 		}
 	})
 
+	t.Run("status", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature-dirty")
+		if err != nil {
+			t.Fatalf("failed to create worktree feature-dirty: %v", err)
+		}
+		wtPath := worktreePath(out)
+
+		if err := os.WriteFile(filepath.Join(wtPath, "README.md"), []byte("# Modified"), 0600); err != nil {
+			t.Fatalf("failed to modify file: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(wtPath, "untracked.txt"), []byte("content"), 0600); err != nil {
+			t.Fatalf("failed to create untracked file: %v", err)
+		}
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root)
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stdout, "STATUS") || !strings.Contains(stdout, "UPSTREAM") {
+			t.Errorf("table header should contain STATUS and UPSTREAM columns, got:\n%s", stdout)
+		}
+		for _, line := range strings.Split(stdout, "\n") {
+			switch {
+			case strings.Contains(line, "feature-dirty"):
+				if !strings.Contains(line, "~1 ?1") {
+					t.Errorf("feature-dirty row should show \"~1 ?1\", got: %q", line)
+				}
+			case strings.Contains(line, " main "):
+				if !strings.Contains(line, "clean") {
+					t.Errorf("main row should show \"clean\", got: %q", line)
+				}
+			}
+		}
+
+		repo.Git("worktree", "lock", "--reason", "pinned", wtPath)
+
+		stdout, stderr, err = runGitWtStdout(t, binPath, repo.Root)
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stdout, "locked ~1 ?1") {
+			t.Errorf("locked worktree row should show \"locked ~1 ?1\", got:\n%s", stdout)
+		}
+
+		stdout, stderr, err = runGitWtStdout(t, binPath, repo.Root, "--json")
+		if err != nil {
+			t.Fatalf("git-wt --json failed: %v\nstderr: %s", err, stderr)
+		}
+		var items []struct {
+			Branch     string `json:"branch"`
+			Locked     bool   `json:"locked"`
+			LockReason string `json:"lock_reason"`
+			Modified   int    `json:"modified"`
+			Untracked  int    `json:"untracked"`
+			Upstream   string `json:"upstream"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &items); err != nil {
+			t.Fatalf("failed to parse JSON output: %v\noutput: %s", err, stdout)
+		}
+		for _, item := range items {
+			if item.Branch != "feature-dirty" {
+				continue
+			}
+			if item.Modified != 1 || item.Untracked != 1 {
+				t.Errorf("feature-dirty JSON status = modified:%d untracked:%d, want 1 and 1", item.Modified, item.Untracked)
+			}
+			if item.Upstream != "" {
+				t.Errorf("feature-dirty upstream = %q, want empty", item.Upstream)
+			}
+			if !item.Locked || item.LockReason != "pinned" {
+				t.Errorf("feature-dirty lock = %v (%q), want locked with reason %q", item.Locked, item.LockReason, "pinned")
+			}
+		}
+	})
+
+	t.Run("format", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "-b", "user/feature", "feature-dir")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--format", "{{if .Current}}*{{end}}|{{.Branch}}|{{.Dir}}|{{.Path}}")
+		if err != nil {
+			t.Fatalf("git-wt --format failed: %v\nstderr: %s", err, stderr)
+		}
+		lines := strings.Split(stdout, "\n")
+		want := []string{
+			"*|main||" + repo.Root,
+			"|user/feature|feature-dir|" + wtPath,
+		}
+		if len(lines) != len(want) {
+			t.Fatalf("expected %d lines, got %d:\n%s", len(want), len(lines), stdout)
+		}
+		for i := range want {
+			if lines[i] != want[i] {
+				t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+			}
+		}
+	})
+
+	t.Run("format_errors", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--format", "{{.Branch")
+		if err == nil {
+			t.Fatal("git-wt --format should fail with an invalid template")
+		}
+		if !strings.Contains(out, "invalid --format template") {
+			t.Errorf("error should mention invalid template, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--format", "{{.Branch}}", "--json")
+		if err == nil {
+			t.Fatal("git-wt --format --json should fail")
+		}
+		if !strings.Contains(out, "cannot use --format with --json") {
+			t.Errorf("error should mention the conflicting flags, got: %s", out)
+		}
+	})
+
 	// Regression test for PR #14 which fixed fish hook output formatting
 	t.Run("table_format_shell", func(t *testing.T) {
 		t.Parallel()
@@ -1,19 +1,27 @@
 // config_test.go contains configuration and flag tests:
 //   - TestE2E_CopyOptions: copy options tests (copyignored config/flag, copyuntracked, copymodified, multiple flags, flag overrides)
+//   - TestE2E_CopyResult: copy summary, --json result and wt.copystrict (summary_and_json, failure_warns, strict_rolls_back)
+//   - TestE2E_CopyFrom: copy source worktree tests (default_config, flag_overrides_config, unknown_source)
+//   - TestE2E_Rollback: wt.rollback tests (flag, config_keeps_existing_branch, cleans_up_nested_parents, existing_empty_directory, copy_failure_in_existing_empty_directory)
 //   - TestE2E_Basedir: basedir tests (config, flag)
 //   - TestE2E_Nocd: nocd tests (config, config_with_init, create_config)
 //   - TestE2E_Hooks: hook tests (flag, config, multiple, not_run_on_existing, flag_overrides_config, failure, output_to_stderr)
 //   - TestE2E_DeleteHooks: delete hook tests (flag, config, multiple, not_run_on_branch_only, flag_overrides_config, failure_prevents_deletion, hook_runs_in_worktree_directory, output_to_stderr)
+//   - TestE2E_ScopedConfig: wt "<pattern>" sections scoped to branches (hook_and_copy, flag_overrides_scoped, switch_and_delete_hooks)
+//   - TestE2E_HookPolicy: hook failure policy and timeout tests (warn_continues, rollback_config, inline_options, timeout, delete_hook_warn, invalid)
 //   - TestE2E_Remover: custom worktree remover tests (flag, config, flag_overrides_config, failure_prevents_deletion, prune_cleans_up)
 //   - TestE2E_Complete: __complete command output tests
 package e2e
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/k1LoW/exec"
 	"github.com/k1LoW/git-wt/testutil"
@@ -280,6 +288,83 @@ func TestE2E_CopyOptions(t *testing.T) {
 	})
 }
 
+func TestE2E_CopyFrom(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	// newRepo returns a repository whose main worktree has an ignored .env,
+	// and a feature worktree with a different one.
+	newRepo := func(t *testing.T) (repo *testutil.TestRepo, featurePath string) {
+		t.Helper()
+		repo = testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".env\n")
+		repo.Commit("initial commit")
+		repo.CreateFile(".env", "FROM=main")
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		featurePath = worktreePath(out)
+		if err := os.WriteFile(filepath.Join(featurePath, ".env"), []byte("FROM=feature"), 0600); err != nil {
+			t.Fatalf("failed to write .env: %v", err)
+		}
+		return repo, featurePath
+	}
+
+	assertEnv := func(t *testing.T, wtPath, want string) {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(wtPath, ".env"))
+		if err != nil {
+			t.Fatalf(".env was not copied to worktree: %v", err)
+		}
+		if string(content) != want {
+			t.Errorf(".env content = %q, want %q", string(content), want)
+		}
+	}
+
+	t.Run("default_config", func(t *testing.T) {
+		t.Parallel()
+		repo, featurePath := newRepo(t)
+		repo.Git("config", "wt.copyfrom", "default")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, featurePath, "--copyignored", "from-default")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\nstderr: %s", err, stderr)
+		}
+		assertEnv(t, worktreePath(stdout), "FROM=main")
+	})
+
+	t.Run("flag_overrides_config", func(t *testing.T) {
+		t.Parallel()
+		repo, _ := newRepo(t)
+		repo.Git("config", "wt.copyfrom", "default")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--copyignored", "--copy-from", "feature", "from-feature")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\nstderr: %s", err, stderr)
+		}
+		assertEnv(t, worktreePath(stdout), "FROM=feature")
+	})
+
+	t.Run("unknown_source", func(t *testing.T) {
+		t.Parallel()
+		repo, _ := newRepo(t)
+
+		_, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--copy-from", "nonexistent", "new-branch")
+		if err == nil {
+			t.Fatal("git-wt should fail for an unknown --copy-from worktree")
+		}
+		if !strings.Contains(stderr, `no worktree found for "nonexistent"`) {
+			t.Errorf("unexpected error: %s", stderr)
+		}
+		if _, err := os.Stat(filepath.Join(repo.Root, ".wt", "new-branch")); !os.IsNotExist(err) {
+			t.Error("worktree should not be created")
+		}
+	})
+}
+
 func TestE2E_Basedir(t *testing.T) {
 	t.Parallel()
 	binPath := buildBinary(t)
@@ -715,6 +800,30 @@ func TestE2E_Hooks(t *testing.T) {
 		}
 	})
 
+	t.Run("environment", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		envFile := filepath.Join(t.TempDir(), "hook-env.txt")
+		out, err := runGitWt(t, binPath, repo.Root, "--hook", "env > "+envFile, "-b", "feature/env", "hook-env-test", "main")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		assertHookEnv(t, envFile, map[string]string{
+			"GIT_WT_ACTION":      "create",
+			"GIT_WT_BRANCH":      "feature/env",
+			"GIT_WT_PATH":        wtPath,
+			"GIT_WT_SOURCE_PATH": repo.Root,
+			"GIT_WT_MAIN_ROOT":   repo.Root,
+			"GIT_WT_START_POINT": "main",
+			"GIT_WT_OLD_PATH":    "",
+		})
+	})
+
 	t.Run("failure_exits_with_error", func(t *testing.T) {
 		t.Parallel()
 		repo := testutil.NewTestRepo(t)
@@ -773,6 +882,529 @@ func TestE2E_Hooks(t *testing.T) {
 	})
 }
 
+func TestE2E_CopyResult(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	// newRepo returns a repository with an ignored .env and, where symlinks
+	// are supported, an ignored dangling symlink that fails to copy.
+	newRepo := func(t *testing.T, dangling bool) *testutil.TestRepo {
+		t.Helper()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".env\n*.log\nbroken\n")
+		repo.Commit("initial commit")
+		repo.CreateFile(".env", "SECRET=value")
+		repo.CreateFile("debug.log", "log")
+		if dangling {
+			if runtime.GOOS == "windows" {
+				t.Skip("symlinks require privileges on Windows")
+			}
+			if err := os.Symlink(filepath.Join(repo.Root, "missing"), filepath.Join(repo.Root, "broken")); err != nil {
+				t.Fatalf("failed to create symlink: %v", err)
+			}
+		}
+		return repo
+	}
+
+	t.Run("summary_and_json", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t, false)
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--copyignored", "--nocopy", "*.log", "--json", "json-test")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stderr, "Copied 1 file (12 B), skipped 1 file by nocopy") {
+			t.Errorf("stderr should contain the copy summary, got: %s", stderr)
+		}
+		var result struct {
+			Action string `json:"action"`
+			Path   string `json:"path"`
+			Branch string `json:"branch"`
+			Copy   struct {
+				Copied   int   `json:"copied"`
+				Bytes    int64 `json:"bytes"`
+				Skipped  int   `json:"skipped"`
+				Failures []any `json:"failures"`
+			} `json:"copy"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+		}
+		if result.Action != "create" || result.Branch != "json-test" || result.Path != filepath.Join(repo.Root, ".wt", "json-test") {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if result.Copy.Copied != 1 || result.Copy.Bytes != 12 || result.Copy.Skipped != 1 || len(result.Copy.Failures) != 0 {
+			t.Errorf("unexpected copy result: %+v", result.Copy)
+		}
+	})
+
+	t.Run("failure_warns", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t, true)
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--copyignored", "warn-test")
+		if err != nil {
+			t.Fatalf("git-wt should not fail without wt.copystrict: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stderr, "warning: failed to copy broken") || !strings.Contains(stderr, "1 failed") {
+			t.Errorf("stderr should report the failure, got: %s", stderr)
+		}
+		assertWorktreeExists(t, worktreePath(stdout))
+	})
+
+	t.Run("strict_rolls_back", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t, true)
+		repo.Git("config", "wt.copystrict", "true")
+
+		_, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--copyignored", "--rollback", "strict-test")
+		if err == nil {
+			t.Fatal("git-wt should fail with wt.copystrict")
+		}
+		if !strings.Contains(stderr, "could not be copied") || !strings.Contains(stderr, "Rolled back worktree") {
+			t.Errorf("stderr should report the strict failure and the rollback, got: %s", stderr)
+		}
+		if strings.Contains(repo.Git("worktree", "list"), "strict-test") {
+			t.Error("worktree should have been removed")
+		}
+	})
+}
+
+func TestE2E_Rollback(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("flag", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--rollback", "--hook", "exit 1", "rollback-test")
+		if err == nil {
+			t.Fatal("command should fail when hook fails")
+		}
+		if !strings.Contains(stderr, "Rolled back worktree") {
+			t.Errorf("stderr should mention the rollback, got: %s", stderr)
+		}
+
+		wtPath := filepath.Join(repo.Root, ".wt", "rollback-test")
+		if strings.Contains(stdout, wtPath) {
+			t.Errorf("worktree path should not be printed after rollback, got: %s", stdout)
+		}
+		if strings.Contains(repo.Git("worktree", "list"), wtPath) {
+			t.Error("worktree should have been removed")
+		}
+		if strings.Contains(repo.Git("branch", "--list", "rollback-test"), "rollback-test") {
+			t.Error("branch created by git-wt should have been deleted")
+		}
+		// The basedir was created for this worktree, so it is cleaned up too
+		if _, err := os.Stat(filepath.Join(repo.Root, ".wt")); !os.IsNotExist(err) {
+			t.Error("basedir created for the worktree should have been removed")
+		}
+	})
+
+	t.Run("config_keeps_existing_branch", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("branch", "existing")
+		repo.Git("config", "wt.rollback", "true")
+
+		_, _, err := runGitWtStdout(t, binPath, repo.Root, "--hook", "exit 1", "existing")
+		if err == nil {
+			t.Fatal("command should fail when hook fails")
+		}
+		if strings.Contains(repo.Git("worktree", "list"), "[existing]") {
+			t.Error("worktree should have been removed")
+		}
+		if !strings.Contains(repo.Git("branch", "--list", "existing"), "existing") {
+			t.Error("existing branch should be kept")
+		}
+	})
+
+	t.Run("cleans_up_nested_parents", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "other")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+
+		_, _, err = runGitWtStdout(t, binPath, repo.Root, "--rollback", "--hook", "exit 1", "feat/nested")
+		if err == nil {
+			t.Fatal("command should fail when hook fails")
+		}
+		if _, err := os.Stat(filepath.Join(repo.Root, ".wt", "feat")); !os.IsNotExist(err) {
+			t.Error("empty parent directory should have been removed")
+		}
+		assertWorktreeExists(t, worktreePath(out))
+	})
+
+	t.Run("existing_empty_directory", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		// git worktree add accepts an existing empty directory
+		wtPath := filepath.Join(repo.Root, ".wt", "empty-dir")
+		if err := os.MkdirAll(wtPath, 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+
+		_, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--rollback", "--hook", "exit 1", "empty-dir")
+		if err == nil {
+			t.Fatal("command should fail when hook fails")
+		}
+		if strings.Contains(stderr, "rollback failed") || !strings.Contains(stderr, "Rolled back worktree") {
+			t.Errorf("stderr should report a successful rollback, got: %s", stderr)
+		}
+		if strings.Contains(repo.Git("worktree", "list"), wtPath) {
+			t.Error("worktree should have been removed")
+		}
+		if strings.Contains(repo.Git("branch", "--list", "empty-dir"), "empty-dir") {
+			t.Error("branch created by git-wt should have been deleted")
+		}
+		if entries, err := os.ReadDir(wtPath); err != nil || len(entries) != 0 {
+			t.Errorf("existing directory should be left empty, got %v (%v)", entries, err)
+		}
+	})
+
+	t.Run("copy_failure_in_existing_empty_directory", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("symlinks require privileges on Windows")
+		}
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", "broken\n")
+		repo.Commit("initial commit")
+		if err := os.Symlink(filepath.Join(repo.Root, "missing"), filepath.Join(repo.Root, "broken")); err != nil {
+			t.Fatalf("failed to create symlink: %v", err)
+		}
+		wtPath := filepath.Join(repo.Root, ".wt", "strict-dir")
+		if err := os.MkdirAll(wtPath, 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+
+		_, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--copyignored", "--copystrict", "--rollback", "strict-dir")
+		if err == nil {
+			t.Fatal("command should fail when a file cannot be copied")
+		}
+		if !strings.Contains(stderr, "Rolled back worktree") {
+			t.Errorf("stderr should mention the rollback, got: %s", stderr)
+		}
+		if strings.Contains(repo.Git("worktree", "list"), wtPath) {
+			t.Error("worktree should have been removed")
+		}
+		if strings.Contains(repo.Git("branch", "--list", "strict-dir"), "strict-dir") {
+			t.Error("branch created by git-wt should have been deleted")
+		}
+	})
+}
+
+func TestE2E_PreCreateHooks(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("veto", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		policy := `case "$GIT_WT_BRANCH" in ABC-*) ;; *) echo "branch must start with ABC-" >&2; exit 1;; esac`
+		repo.Git("config", "--add", "wt.precreatehook", policy)
+
+		_, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--hook", "touch hook-marker.txt", "no-ticket")
+		if err == nil {
+			t.Fatal("command should fail when precreate hook fails")
+		}
+		if !strings.Contains(stderr, "branch must start with ABC-") {
+			t.Errorf("error should carry the hook's stderr, got: %s", stderr)
+		}
+		if _, err := os.Stat(filepath.Join(repo.Root, ".wt")); !os.IsNotExist(err) {
+			t.Error("nothing should be created when precreate hook fails")
+		}
+		if strings.Contains(repo.Git("branch", "--list", "no-ticket"), "no-ticket") {
+			t.Error("branch should not be created when precreate hook fails")
+		}
+
+		out, err := runGitWt(t, binPath, repo.Root, "ABC-123")
+		if err != nil {
+			t.Fatalf("precreate hook should allow ABC-123: %v\noutput: %s", err, out)
+		}
+		assertWorktreeExists(t, worktreePath(out))
+	})
+
+	t.Run("flag_environment", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "source")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		srcPath := worktreePath(out)
+
+		dir := t.TempDir()
+		envFile := filepath.Join(dir, "hook-env.txt")
+		pwdFile := filepath.Join(dir, "hook-pwd.txt")
+		hook := fmt.Sprintf("env > %s; pwd > %s", envFile, pwdFile)
+		out, err = runGitWt(t, binPath, srcPath, "--precreatehook", hook, "precreate-env-test", "main")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+
+		assertHookEnv(t, envFile, map[string]string{
+			"GIT_WT_ACTION":      "create",
+			"GIT_WT_BRANCH":      "precreate-env-test",
+			"GIT_WT_PATH":        worktreePath(out),
+			"GIT_WT_SOURCE_PATH": srcPath,
+			"GIT_WT_MAIN_ROOT":   repo.Root,
+			"GIT_WT_START_POINT": "main",
+		})
+
+		// Pre-create hooks run in the main repository root
+		content, err := os.ReadFile(pwdFile)
+		if err != nil {
+			t.Fatalf("pwd file was not created: %v", err)
+		}
+		if got := strings.TrimSpace(string(content)); got != repo.Root {
+			t.Errorf("hook working directory = %q, want %q", got, repo.Root)
+		}
+	})
+}
+
+func TestE2E_HookPolicy(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("warn_continues", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--hookfailure", "warn", "--hook", "exit 1", "--hook", "touch after", "warn-test")
+		if err != nil {
+			t.Fatalf("command should succeed with --hookfailure warn: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stderr, "warning: hook \"exit 1\" failed") {
+			t.Errorf("stderr should contain a warning, got: %s", stderr)
+		}
+		wtPath := worktreePath(stdout)
+		if _, err := os.Stat(filepath.Join(wtPath, "after")); err != nil {
+			t.Error("hooks after the failed hook should still run")
+		}
+	})
+
+	t.Run("rollback_config", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.hookfailure", "rollback")
+
+		_, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--hook", "exit 1", "rollback-policy-test")
+		if err == nil {
+			t.Fatal("command should fail when hook fails")
+		}
+		if !strings.Contains(stderr, "Rolled back worktree") {
+			t.Errorf("stderr should mention the rollback, got: %s", stderr)
+		}
+		if strings.Contains(repo.Git("branch", "--list", "rollback-policy-test"), "rollback-policy-test") {
+			t.Error("branch created by git-wt should have been deleted")
+		}
+	})
+
+	t.Run("inline_options", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		_, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--hook", "[failure=rollback] exit 1", "inline-test")
+		if err == nil {
+			t.Fatal("command should fail when hook fails")
+		}
+		if !strings.Contains(stderr, "Rolled back worktree") {
+			t.Errorf("stderr should mention the rollback, got: %s", stderr)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("sh process groups are not available on Windows")
+		}
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.hooktimeout", "500ms")
+
+		start := time.Now()
+		_, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--hook", "sleep 30", "timeout-test")
+		if err == nil {
+			t.Fatal("command should fail when hook times out")
+		}
+		if !strings.Contains(stderr, "timed out after 500ms") {
+			t.Errorf("stderr should mention the timeout, got: %s", stderr)
+		}
+		if elapsed := time.Since(start); elapsed > 20*time.Second {
+			t.Errorf("git-wt took %s, the hook should have been killed", elapsed)
+		}
+	})
+
+	t.Run("delete_hook_warn", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "delete-warn-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "--deletehook", "[failure=warn] exit 1", "delete-warn-test")
+		if err != nil {
+			t.Fatalf("delete should succeed with a warn hook: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, "warning: hook") {
+			t.Errorf("output should contain a warning, got: %s", out)
+		}
+		if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+			t.Error("worktree should have been deleted")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--hookfailure", "ignore", "invalid-test")
+		if err == nil {
+			t.Fatal("command should fail with an invalid failure policy")
+		}
+		if !strings.Contains(out, "invalid wt.hookfailure") {
+			t.Errorf("unexpected error: %s", out)
+		}
+		if strings.Contains(repo.Git("branch", "--list", "invalid-test"), "invalid-test") {
+			t.Error("branch should not be created")
+		}
+	})
+}
+
+func TestE2E_ScopedConfig(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("hook_and_copy", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", "*.local\n")
+		repo.Commit("initial commit")
+		repo.CreateFile("frontend.local", "frontend")
+		repo.CreateFile("backend.local", "backend")
+		repo.Git("config", "--add", "wt.hook", "touch global")
+		repo.Git("config", "--add", "wt.frontend/*.hook", "touch frontend")
+		repo.Git("config", "--add", "wt.frontend/*.copy", "frontend.local")
+		repo.Git("config", "--add", "wt.backend/*.copy", "backend.local")
+
+		out, err := runGitWt(t, binPath, repo.Root, "frontend/login")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		for _, name := range []string{"global", "frontend", "frontend.local"} {
+			if _, err := os.Stat(filepath.Join(wtPath, name)); err != nil {
+				t.Errorf("%s should exist in the frontend worktree", name)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(wtPath, "backend.local")); !os.IsNotExist(err) {
+			t.Error("backend.local should not be copied to the frontend worktree")
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "backend/api")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\noutput: %s", err, out)
+		}
+		wtPath = worktreePath(out)
+		if _, err := os.Stat(filepath.Join(wtPath, "frontend")); !os.IsNotExist(err) {
+			t.Error("frontend hook should not run for the backend worktree")
+		}
+		if _, err := os.Stat(filepath.Join(wtPath, "backend.local")); err != nil {
+			t.Error("backend.local should be copied to the backend worktree")
+		}
+	})
+
+	t.Run("flag_overrides_scoped", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "--add", "wt.frontend/*.hook", "touch scoped")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--hook", "touch flag", "frontend/flag")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		if _, err := os.Stat(filepath.Join(wtPath, "flag")); err != nil {
+			t.Error("flag hook should run")
+		}
+		if _, err := os.Stat(filepath.Join(wtPath, "scoped")); !os.IsNotExist(err) {
+			t.Error("--hook should replace scoped hooks")
+		}
+	})
+
+	t.Run("switch_and_delete_hooks", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		marker := filepath.Join(t.TempDir(), "deleted")
+		repo.Git("config", "--add", "wt.frontend/.switchhook", "echo switched-frontend")
+		repo.Git("config", "--add", "wt.frontend/.deletehook", "touch "+marker)
+
+		out, err := runGitWt(t, binPath, repo.Root, "-b", "frontend/ui", "ui")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\noutput: %s", err, out)
+		}
+
+		// Switching by directory name scopes by the worktree's branch
+		out, err = runGitWt(t, binPath, repo.Root, "ui")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, "switched-frontend") {
+			t.Errorf("scoped switch hook should run, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "ui")
+		if err != nil {
+			t.Fatalf("git-wt -D failed: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(marker); err != nil {
+			t.Error("scoped delete hook should run")
+		}
+	})
+}
+
 func TestE2E_DeleteHooks(t *testing.T) {
 	t.Parallel()
 	binPath := buildBinary(t)
@@ -934,6 +1566,34 @@ func TestE2E_DeleteHooks(t *testing.T) {
 		}
 	})
 
+	t.Run("environment", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "delete-hook-env-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		envFile := filepath.Join(t.TempDir(), "hook-env.txt")
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "--deletehook", "env > "+envFile, "delete-hook-env-test")
+		if err != nil {
+			t.Fatalf("git-wt -D --deletehook failed: %v\noutput: %s", err, out)
+		}
+
+		assertHookEnv(t, envFile, map[string]string{
+			"GIT_WT_ACTION":      "delete",
+			"GIT_WT_BRANCH":      "delete-hook-env-test",
+			"GIT_WT_PATH":        wtPath,
+			"GIT_WT_SOURCE_PATH": repo.Root,
+			"GIT_WT_MAIN_ROOT":   repo.Root,
+			"GIT_WT_START_POINT": "",
+		})
+	})
+
 	t.Run("hook_runs_in_worktree_directory", func(t *testing.T) {
 		t.Parallel()
 		repo := testutil.NewTestRepo(t)
@@ -990,6 +1650,168 @@ func TestE2E_DeleteHooks(t *testing.T) {
 	})
 }
 
+func TestE2E_SwitchHooks(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("flag", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--switchhook", "touch switch-marker.txt", "switch-hook-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		markerPath := filepath.Join(wtPath, "switch-marker.txt")
+		if _, err := os.Stat(markerPath); !os.IsNotExist(err) {
+			t.Error("switch hook should NOT run when creating a worktree")
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--switchhook", "touch switch-marker.txt", "switch-hook-test")
+		if err != nil {
+			t.Fatalf("failed to switch to worktree: %v\noutput: %s", err, out)
+		}
+		if worktreePath(out) != wtPath {
+			t.Errorf("switch should print the worktree path %s, got: %s", wtPath, out)
+		}
+		if _, err := os.Stat(markerPath); os.IsNotExist(err) {
+			t.Error("switch-marker.txt was not created by switch hook")
+		}
+	})
+
+	t.Run("config_environment", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "switch-hook-env-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		envFile := filepath.Join(t.TempDir(), "hook-env.txt")
+		repo.Git("config", "--add", "wt.switchhook", "env > "+envFile)
+		out, err = runGitWt(t, binPath, repo.Root, "switch-hook-env-test")
+		if err != nil {
+			t.Fatalf("failed to switch to worktree: %v\noutput: %s", err, out)
+		}
+
+		assertHookEnv(t, envFile, map[string]string{
+			"GIT_WT_ACTION":      "switch",
+			"GIT_WT_BRANCH":      "switch-hook-env-test",
+			"GIT_WT_PATH":        wtPath,
+			"GIT_WT_SOURCE_PATH": repo.Root,
+			"GIT_WT_MAIN_ROOT":   repo.Root,
+		})
+	})
+
+	t.Run("failure_exits_with_error", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "switch-hook-fail-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+
+		_, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--switchhook", "exit 1", "switch-hook-fail-test")
+		if err == nil {
+			t.Fatal("command should fail when switch hook fails")
+		}
+		if !strings.Contains(stderr, "hook") || !strings.Contains(stderr, "failed") {
+			t.Errorf("stderr should contain error about failed hook, got: %s", stderr)
+		}
+	})
+}
+
+func TestE2E_MoveHooks(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("flag_environment", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "move-hook-old")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		oldPath := worktreePath(out)
+		newPath := filepath.Join(filepath.Dir(oldPath), "move-hook-new")
+
+		envFile := filepath.Join(t.TempDir(), "hook-env.txt")
+		out, err = runGitWt(t, binPath, repo.Root, "-m", "--movehook", "env > "+envFile, "--movehook", "touch move-marker.txt", "move-hook-old", "move-hook-new")
+		if err != nil {
+			t.Fatalf("git-wt -m --movehook failed: %v\noutput: %s", err, out)
+		}
+
+		if _, err := os.Stat(filepath.Join(newPath, "move-marker.txt")); err != nil {
+			t.Errorf("move hook should run in the renamed worktree: %v", err)
+		}
+		assertHookEnv(t, envFile, map[string]string{
+			"GIT_WT_ACTION":    "move",
+			"GIT_WT_BRANCH":    "move-hook-new",
+			"GIT_WT_PATH":      newPath,
+			"GIT_WT_OLD_PATH":  oldPath,
+			"GIT_WT_MAIN_ROOT": repo.Root,
+		})
+	})
+
+	t.Run("config", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "move-hook-config-old")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		newPath := filepath.Join(filepath.Dir(worktreePath(out)), "move-hook-config-new")
+
+		repo.Git("config", "--add", "wt.movehook", "touch move-marker.txt")
+		out, err = runGitWt(t, binPath, repo.Root, "-m", "move-hook-config-old", "move-hook-config-new")
+		if err != nil {
+			t.Fatalf("git-wt -m failed: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(filepath.Join(newPath, "move-marker.txt")); err != nil {
+			t.Errorf("move-marker.txt was not created by move hook from config: %v", err)
+		}
+	})
+
+	t.Run("failure_exits_with_error", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "move-hook-fail-old")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		newPath := filepath.Join(filepath.Dir(worktreePath(out)), "move-hook-fail-new")
+
+		out, err = runGitWt(t, binPath, repo.Root, "-m", "--movehook", "exit 1", "move-hook-fail-old", "move-hook-fail-new")
+		if err == nil {
+			t.Fatal("command should fail when move hook fails")
+		}
+		if !strings.Contains(out, "move hook failed") {
+			t.Errorf("output should mention the failed move hook, got: %s", out)
+		}
+		// The rename itself is kept
+		assertWorktreeExists(t, newPath)
+	})
+}
+
 func TestE2E_Relative(t *testing.T) {
 	t.Parallel()
 	binPath := buildBinary(t)
@@ -1252,6 +2074,33 @@ func TestE2E_Remover(t *testing.T) {
 		}
 	})
 
+	t.Run("environment", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "remover-env-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		envFile := filepath.Join(t.TempDir(), "remover-env.txt")
+		remover := fmt.Sprintf(`f() { env > %s; rm -rf "$1"; }; f`, envFile)
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "--remover", remover, "remover-env-test")
+		if err != nil {
+			t.Fatalf("git-wt -D --remover failed: %v\noutput: %s", err, out)
+		}
+
+		assertHookEnv(t, envFile, map[string]string{
+			"GIT_WT_ACTION":    "delete",
+			"GIT_WT_BRANCH":    "remover-env-test",
+			"GIT_WT_PATH":      wtPath,
+			"GIT_WT_MAIN_ROOT": repo.Root,
+		})
+	})
+
 	t.Run("prune_cleans_up", func(t *testing.T) {
 		t.Parallel()
 		repo := testutil.NewTestRepo(t)
@@ -1488,3 +2337,36 @@ func TestE2E_Complete(t *testing.T) {
 		}
 	})
 }
+
+// assertHookEnv asserts that the environment dumped by 'env > file' in a hook
+// contains the given GIT_WT_* variables. Paths are compared after resolving
+// symlinks (macOS /var vs /private/var).
+func assertHookEnv(t *testing.T, file string, want map[string]string) {
+	t.Helper()
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("hook environment was not written: %v", err)
+	}
+	got := make(map[string]string)
+	for line := range strings.Lines(string(content)) {
+		if k, v, ok := strings.Cut(strings.TrimSuffix(line, "\n"), "="); ok && strings.HasPrefix(k, "GIT_WT_") {
+			got[k] = v
+		}
+	}
+	resolve := func(p string) string {
+		if r, err := filepath.EvalSymlinks(p); err == nil {
+			return r
+		}
+		return p
+	}
+	for k, v := range want {
+		g, ok := got[k]
+		if !ok {
+			t.Errorf("%s is not set", k)
+			continue
+		}
+		if g != v && resolve(g) != resolve(v) {
+			t.Errorf("%s = %q, want %q", k, g, v)
+		}
+	}
+}
@@ -119,7 +119,7 @@ func TestE2E_DeleteDefaultBranch(t *testing.T) {
 		}
 
 		// Try to delete multiple branches including main
-		// feature-a is deleted first, then main fails, feature-b is not processed
+		// All targets are validated first, so main fails before anything is deleted
 		out, err := runGitWt(t, binPath, repo.Root, "-D", "feature-a", "main", "feature-b")
 		if err == nil {
 			t.Fatal("should fail when deleting multiple branches including default")
@@ -128,18 +128,18 @@ func TestE2E_DeleteDefaultBranch(t *testing.T) {
 			t.Errorf("error should mention default branch protection, got: %s", out)
 		}
 
-		// feature-a IS deleted (processed before main)
+		// feature-a should NOT be deleted (validation fails at main)
 		cmd = exec.Command("git", "branch", "--list", "feature-a")
 		cmd.Dir = repo.Root
 		branchOut, err := cmd.Output()
 		if err != nil {
 			t.Fatalf("git branch --list failed: %v", err)
 		}
-		if strings.Contains(string(branchOut), "feature-a") {
-			t.Error("feature-a should have been deleted (processed before main)")
+		if !strings.Contains(string(branchOut), "feature-a") {
+			t.Error("feature-a should still exist (validation fails at main)")
 		}
 
-		// feature-b should NOT be deleted (processing stops at main)
+		// feature-b should NOT be deleted (validation fails at main)
 		cmd = exec.Command("git", "branch", "--list", "feature-b")
 		cmd.Dir = repo.Root
 		branchOut, err = cmd.Output()
@@ -147,7 +147,7 @@ func TestE2E_DeleteDefaultBranch(t *testing.T) {
 			t.Fatalf("git branch --list failed: %v", err)
 		}
 		if !strings.Contains(string(branchOut), "feature-b") {
-			t.Error("feature-b should still exist (processing stops at main)")
+			t.Error("feature-b should still exist (validation fails at main)")
 		}
 
 		// main should still exist
@@ -165,6 +165,29 @@ func TestE2E_DeleteWorktree(t *testing.T) {
 		}
 	})
 
+	t.Run("safe_delete_locked", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "locked-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v", err)
+		}
+		wtPath := worktreePath(out)
+		repo.Git("worktree", "lock", "--reason", "keep me", wtPath)
+
+		out, err = runGitWt(t, binPath, repo.Root, "-d", "locked-test")
+		if err == nil {
+			t.Fatal("git-wt -d should fail when worktree is locked")
+		}
+		if !strings.Contains(out, "is locked (reason: keep me)") {
+			t.Errorf("error should mention the lock and its reason, got: %s", out)
+		}
+		assertWorktreeExists(t, wtPath)
+	})
+
 	// PR #64 fix: worktree deletion succeeds even when branch deletion fails
 	t.Run("with_unmerged_branch", func(t *testing.T) {
 		t.Parallel()
@@ -209,6 +232,86 @@ func TestE2E_DeleteWorktree(t *testing.T) {
 		}
 	})
 
+	t.Run("with_squash_merged_branch", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "squashed")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v", err)
+		}
+		wtPath := worktreePath(out)
+
+		commitUnmergedChange(t, wtPath)
+
+		// Squash-merge into main, then check out another branch so that the
+		// merge check cannot rely on HEAD
+		repo.Git("merge", "--squash", "squashed")
+		repo.Commit("squash merge")
+		repo.Git("checkout", "-b", "other", "main~1")
+
+		out, err = runGitWt(t, binPath, repo.Root, "-d", "squashed")
+		if err != nil {
+			t.Fatalf("git-wt -d failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, `Deleted worktree and branch "squashed"`) {
+			t.Errorf("output should confirm worktree and branch deletion, got: %s", out)
+		}
+		if strings.Contains(repo.Git("branch", "--list", "squashed"), "squashed") {
+			t.Error("squash-merged branch should be deleted with -d")
+		}
+	})
+
+	t.Run("with_unpushed_commits", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("branch", "pushed")
+
+		out, err := runGitWt(t, binPath, repo.Root, "unpushed")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v", err)
+		}
+		wtPath := worktreePath(out)
+
+		// Merged into local main, but missing from the upstream
+		commitUnmergedChange(t, wtPath)
+		repo.Git("merge", "unpushed")
+		repo.Git("branch", "--set-upstream-to=pushed", "unpushed")
+
+		out, err = runGitWt(t, binPath, repo.Root, "-d", "unpushed")
+		if err == nil {
+			t.Fatal("git-wt -d should fail when the branch has unpushed commits")
+		}
+		if !strings.Contains(out, `branch "unpushed" has 1 unpushed commit(s)`) {
+			t.Errorf("error should mention the unpushed commits, got: %s", out)
+		}
+		if !strings.Contains(out, "use -D to force") {
+			t.Errorf("error should suggest using -D to force, got: %s", out)
+		}
+		if _, err := os.Stat(wtPath); err != nil {
+			t.Errorf("worktree should still exist: %v", err)
+		}
+
+		// Branch-only deletion is refused as well
+		repo.Git("worktree", "remove", wtPath)
+		out, err = runGitWt(t, binPath, repo.Root, "-d", "unpushed")
+		if err == nil {
+			t.Fatal("git-wt -d should fail when the branch has unpushed commits")
+		}
+		if !strings.Contains(out, "unpushed commit(s)") {
+			t.Errorf("error should mention the unpushed commits, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "unpushed")
+		if err != nil {
+			t.Fatalf("git-wt -D failed: %v\noutput: %s", err, out)
+		}
+	})
+
 	// PR #137 fix: delete worktree from a derived branch should succeed
 	// When on branch b (derived from a), deleting worktree a with -d should
 	// succeed because a is fully merged into the current HEAD (b).
@@ -416,10 +519,11 @@ pwd
 		repo.CreateFile("README.md", "# Test")
 		repo.Commit("initial commit")
 
-		_, err := runGitWt(t, binPath, repo.Root, "stop-a")
+		outA, err := runGitWt(t, binPath, repo.Root, "stop-a")
 		if err != nil {
 			t.Fatalf("failed to create worktree stop-a: %v", err)
 		}
+		pathA := worktreePath(outA)
 		outC, err := runGitWt(t, binPath, repo.Root, "stop-c")
 		if err != nil {
 			t.Fatalf("failed to create worktree stop-c: %v", err)
@@ -436,11 +540,84 @@ pwd
 			t.Errorf("error should mention 'stop-b', got: %s", out)
 		}
 
-		// stop-c should NOT be deleted (execution stopped at stop-b error)
+		// Neither stop-a nor stop-c should be deleted (validation fails at stop-b)
+		if _, err := os.Stat(pathA); os.IsNotExist(err) {
+			t.Error("stop-a should NOT have been deleted (all targets are validated first)")
+		}
 		if _, err := os.Stat(pathC); os.IsNotExist(err) {
 			t.Error("stop-c should NOT have been deleted (execution should stop on error)")
 		}
 	})
+
+	t.Run("multiple_including_current", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		outA, err := runGitWt(t, binPath, repo.Root, "cur-a")
+		if err != nil {
+			t.Fatalf("failed to create worktree cur-a: %v", err)
+		}
+		pathA := worktreePath(outA)
+		outB, err := runGitWt(t, binPath, repo.Root, "cur-b")
+		if err != nil {
+			t.Fatalf("failed to create worktree cur-b: %v", err)
+		}
+		pathB := worktreePath(outB)
+
+		// The current worktree is listed first but must be deleted last
+		out, err := runGitWt(t, binPath, pathA, "-d", "cur-a", "cur-b")
+		if err != nil {
+			t.Fatalf("git-wt -d failed: %v\noutput: %s", err, out)
+		}
+		for _, p := range []string{pathA, pathB} {
+			if _, err := os.Stat(p); !os.IsNotExist(err) {
+				t.Errorf("%s should have been deleted", p)
+			}
+		}
+	})
+
+	t.Run("multiple_keep_going", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		paths := map[string]string{}
+		for _, b := range []string{"go-a", "go-dirty", "go-c"} {
+			out, err := runGitWt(t, binPath, repo.Root, b)
+			if err != nil {
+				t.Fatalf("failed to create worktree %s: %v", b, err)
+			}
+			paths[b] = worktreePath(out)
+		}
+		if err := os.WriteFile(filepath.Join(paths["go-dirty"], "untracked.txt"), []byte("x"), 0600); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+
+		out, err := runGitWt(t, binPath, repo.Root, "-d", "--keep-going", "go-a", "go-missing", "go-dirty", "go-c")
+		if err == nil {
+			t.Fatal("command should fail when some targets could not be deleted")
+		}
+		if !strings.Contains(out, "2 of 4 targets could not be deleted") {
+			t.Errorf("error should summarize the failures, got: %s", out)
+		}
+		for _, want := range []string{"TARGET", "RESULT", "go-missing", "no worktree or branch found", "has untracked files", "deleted worktree and branch"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("result table should contain %q, got: %s", want, out)
+			}
+		}
+
+		for _, b := range []string{"go-a", "go-c"} {
+			if _, err := os.Stat(paths[b]); !os.IsNotExist(err) {
+				t.Errorf("%s should have been deleted", b)
+			}
+		}
+		if _, err := os.Stat(paths["go-dirty"]); err != nil {
+			t.Errorf("go-dirty should be kept: %v", err)
+		}
+	})
 }
 
 func TestE2E_DeleteBranch(t *testing.T) {
@@ -0,0 +1,229 @@
+// dryrun_test.go contains --dry-run tests:
+//   - TestE2E_DryRun: plans for create, switch, delete and move (text and JSON)
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestE2E_DryRun(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("create", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".env\n")
+		repo.Commit("initial commit")
+		repo.CreateFile(".env", "SECRET=value")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--dry-run", "--copyignored", "--hook", "touch hooked", "feature", "main")
+		if err != nil {
+			t.Fatalf("git-wt --dry-run failed: %v\nstderr: %s", err, stderr)
+		}
+		wtPath := filepath.Join(repo.Root, ".wt", "feature")
+		for _, want := range []string{
+			"Would create worktree at " + wtPath,
+			"branch: feature (new, from main)",
+			"copy: .env",
+			"hook: touch hooked",
+		} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("output should contain %q, got:\n%s", want, stdout)
+			}
+		}
+		// The shell wrapper must not cd anywhere
+		if info, err := os.Stat(worktreePath(stdout)); err == nil && info.IsDir() {
+			t.Errorf("last line of the plan should not be a directory: %q", worktreePath(stdout))
+		}
+
+		if _, err := os.Stat(filepath.Join(repo.Root, ".wt")); !os.IsNotExist(err) {
+			t.Error("--dry-run should not create the base directory")
+		}
+		if strings.Contains(repo.Git("branch", "--list", "feature"), "feature") {
+			t.Error("--dry-run should not create the branch")
+		}
+	})
+
+	t.Run("create_json", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("branch", "existing")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--dry-run", "--json", "existing")
+		if err != nil {
+			t.Fatalf("git-wt --dry-run --json failed: %v\nstderr: %s", err, stderr)
+		}
+		var plan struct {
+			Action       string   `json:"action"`
+			Path         string   `json:"path"`
+			Branch       string   `json:"branch"`
+			CreateBranch bool     `json:"create_branch"`
+			Copy         []string `json:"copy"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &plan); err != nil {
+			t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+		}
+		if plan.Action != "create" || plan.Branch != "existing" || plan.CreateBranch {
+			t.Errorf("unexpected plan: %+v", plan)
+		}
+		if plan.Path != filepath.Join(repo.Root, ".wt", "existing") {
+			t.Errorf("path = %q, want %q", plan.Path, filepath.Join(repo.Root, ".wt", "existing"))
+		}
+		if plan.Copy == nil {
+			t.Error("copy should be an empty list, not null")
+		}
+	})
+
+	t.Run("switch", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--dry-run", "feature")
+		if err != nil {
+			t.Fatalf("git-wt --dry-run failed: %v\nstderr: %s", err, stderr)
+		}
+		if stdout != "Would switch to existing worktree at "+wtPath {
+			t.Errorf("unexpected output: %q", stdout)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		repo.Git("config", "wt.deletehook", "echo bye")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "-d", "--dry-run", "feature")
+		if err != nil {
+			t.Fatalf("git-wt -d --dry-run failed: %v\nstderr: %s", err, stderr)
+		}
+		for _, want := range []string{
+			`Would remove worktree "feature"`,
+			"path: " + wtPath,
+			"deletehook: echo bye",
+			"branch: feature (deleted)",
+		} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("output should contain %q, got:\n%s", want, stdout)
+			}
+		}
+		assertWorktreeExists(t, wtPath)
+		if !strings.Contains(repo.Git("branch", "--list", "feature"), "feature") {
+			t.Error("--dry-run should not delete the branch")
+		}
+	})
+
+	t.Run("delete_reports_all_failures", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		stdout, _, err := runGitWtStdout(t, binPath, repo.Root, "-d", "--dry-run", "--json", "missing", "feature")
+		if err == nil {
+			t.Fatal("git-wt -d --dry-run should fail when a target cannot be deleted")
+		}
+		var plans []struct {
+			Target   string `json:"target"`
+			Worktree string `json:"worktree"`
+			Skip     string `json:"skip"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &plans); err != nil {
+			t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+		}
+		if len(plans) != 2 {
+			t.Fatalf("expected 2 plans, got %d: %s", len(plans), stdout)
+		}
+		if plans[0].Target != "missing" || plans[0].Skip == "" {
+			t.Errorf("missing should be skipped: %+v", plans[0])
+		}
+		if plans[1].Target != "feature" || plans[1].Worktree != wtPath || plans[1].Skip != "" {
+			t.Errorf("feature should be planned for deletion: %+v", plans[1])
+		}
+		assertWorktreeExists(t, wtPath)
+	})
+
+	t.Run("move", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "old")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		oldPath := worktreePath(out)
+		newPath := filepath.Join(filepath.Dir(oldPath), "new")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "-m", "--dry-run", "old", "new")
+		if err != nil {
+			t.Fatalf("git-wt -m --dry-run failed: %v\nstderr: %s", err, stderr)
+		}
+		lines := strings.Split(stdout, "\n")
+		want := []string{
+			"Would move worktree from " + oldPath + " to " + newPath,
+			`Would rename branch "old" to "new"`,
+		}
+		if !slices.Equal(lines, want) {
+			t.Errorf("output = %q, want %q", lines, want)
+		}
+		assertWorktreeExists(t, oldPath)
+		if _, err := os.Stat(newPath); !os.IsNotExist(err) {
+			t.Error("--dry-run should not move the worktree")
+		}
+	})
+
+	t.Run("invalid_usage", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		for _, args := range [][]string{
+			{"--dry-run"},
+			{"--dry-run", "--lock", "main"},
+		} {
+			out, err := runGitWt(t, binPath, repo.Root, args...)
+			if err == nil {
+				t.Errorf("git-wt %v should fail", args)
+				continue
+			}
+			if !strings.Contains(out, "--dry-run can only be used") {
+				t.Errorf("unexpected error for %v: %s", args, out)
+			}
+		}
+	})
+}
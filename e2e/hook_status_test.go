@@ -0,0 +1,182 @@
+// hook_status_test.go contains background hook tests:
+//   - TestE2E_AsyncHooks: async hooks run detached, report their status and keep a log
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestE2E_AsyncHooks(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("does_not_block", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		start := time.Now()
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--hook", "touch sync", "--hook", "[async] sleep 2 && echo \"done $GIT_WT_BRANCH\" && touch async", "async-test")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\nstderr: %s", err, stderr)
+		}
+		// The shell integration reads stdout until EOF, so the background
+		// hook must not hold it open
+		if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+			t.Errorf("git-wt took %s, async hooks should not block", elapsed)
+		}
+		wtPath := worktreePath(stdout)
+		if wtPath != filepath.Join(repo.Root, ".wt", "async-test") {
+			t.Errorf("last line should be the worktree path, got: %q", stdout)
+		}
+		if !strings.Contains(stderr, "in the background") {
+			t.Errorf("stderr should mention the background hooks, got: %s", stderr)
+		}
+		if _, err := os.Stat(filepath.Join(wtPath, "sync")); err != nil {
+			t.Error("synchronous hook should have run")
+		}
+
+		out, err := runGitWt(t, binPath, repo.Root, "--hook-status", "async-test")
+		if err != nil {
+			t.Fatalf("git-wt --hook-status failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, `Background hooks for "async-test": running`) {
+			t.Errorf("hooks should be running, got: %s", out)
+		}
+
+		stdout, stderr, err = runGitWtStdout(t, binPath, repo.Root, "--hook-status", "--follow", "async-test")
+		if err != nil {
+			t.Fatalf("git-wt --hook-status --follow failed: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stdout, "done async-test") {
+			t.Errorf("log should contain the hook output, got: %s", stdout)
+		}
+		if !strings.HasSuffix(stdout, `Background hooks for "async-test" succeeded`) {
+			t.Errorf("last line should be the final status, got: %s", stdout)
+		}
+		if _, err := os.Stat(filepath.Join(wtPath, "async")); err != nil {
+			t.Error("async hook should have run in the worktree")
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--hook", "[async] echo broken >&2; exit 3", "async-fail")
+		if err != nil {
+			t.Fatalf("git-wt should not fail for a background hook: %v\noutput: %s", err, out)
+		}
+
+		stdout, _, err := runGitWtStdout(t, binPath, repo.Root, "--hook-status", "--follow", "async-fail")
+		if err == nil {
+			t.Fatal("--follow should fail when the background hooks fail")
+		}
+		if !strings.Contains(stdout, "broken") {
+			t.Errorf("log should contain the hook stderr, got: %s", stdout)
+		}
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--hook-status", "--json")
+		if err != nil {
+			t.Fatalf("git-wt --hook-status --json failed: %v\nstderr: %s", err, stderr)
+		}
+		var jobs []struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+			Env    struct {
+				Branch string `json:"branch"`
+			} `json:"env"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &jobs); err != nil {
+			t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+		}
+		if len(jobs) != 1 || jobs[0].Status != "failed" || jobs[0].Env.Branch != "async-fail" || jobs[0].Error == "" {
+			t.Errorf("unexpected jobs: %+v", jobs)
+		}
+	})
+
+	t.Run("removed_with_worktree", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--hook", "[async] true", "async-delete")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\noutput: %s", err, out)
+		}
+		if out, err := runGitWt(t, binPath, repo.Root, "--hook-status", "--follow", "async-delete"); err != nil {
+			t.Fatalf("git-wt --hook-status --follow failed: %v\noutput: %s", err, out)
+		}
+		if out, err := runGitWt(t, binPath, repo.Root, "-D", "async-delete"); err != nil {
+			t.Fatalf("git-wt -D failed: %v\noutput: %s", err, out)
+		}
+
+		stdout, _, err := runGitWtStdout(t, binPath, repo.Root, "--hook-status", "--json")
+		if err != nil {
+			t.Fatalf("git-wt --hook-status --json failed: %v", err)
+		}
+		if stdout != "[]" {
+			t.Errorf("background hooks of the deleted worktree should be gone, got: %s", stdout)
+		}
+	})
+
+	t.Run("kept_when_removal_fails", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--hook", "[async] true", "async-keep")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\noutput: %s", err, out)
+		}
+		if out, err := runGitWt(t, binPath, repo.Root, "--hook-status", "--follow", "async-keep"); err != nil {
+			t.Fatalf("git-wt --hook-status --follow failed: %v\noutput: %s", err, out)
+		}
+		if out, err := runGitWt(t, binPath, repo.Root, "-D", "--remover", "false", "async-keep"); err == nil {
+			t.Fatalf("git-wt -D should fail when the remover fails\noutput: %s", out)
+		}
+
+		stdout, _, err := runGitWtStdout(t, binPath, repo.Root, "--hook-status", "--json")
+		if err != nil {
+			t.Fatalf("git-wt --hook-status --json failed: %v", err)
+		}
+		var jobs []struct {
+			Env struct {
+				Branch string `json:"branch"`
+			} `json:"env"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &jobs); err != nil {
+			t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+		}
+		if len(jobs) != 1 || jobs[0].Env.Branch != "async-keep" {
+			t.Errorf("background hooks of the kept worktree should remain, got: %s", stdout)
+		}
+	})
+
+	t.Run("precreate_rejected", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--precreatehook", "[async] true", "async-pre")
+		if err == nil {
+			t.Fatal("async precreate hooks should be rejected")
+		}
+		if !strings.Contains(out, "cannot run in the background") {
+			t.Errorf("unexpected error: %s", out)
+		}
+	})
+}
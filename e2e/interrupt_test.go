@@ -0,0 +1,73 @@
+// interrupt_test.go contains signal handling tests:
+//   - TestE2E_Interrupt: Ctrl-C during creation rolls back the partial worktree
+package e2e
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestE2E_Interrupt(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("sending SIGINT is not supported on Windows")
+	}
+	binPath := buildBinary(t)
+
+	t.Run("during_hook", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		wtPath := filepath.Join(repo.Root, ".wt", "interrupted")
+		cmd := exec.Command(binPath, "--hook", "touch started && sleep 30", "interrupted")
+		cmd.Dir = repo.Root
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("failed to start git-wt: %v", err)
+		}
+
+		// Wait until the hook runs, i.e. the worktree has been created
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			if _, err := os.Stat(filepath.Join(wtPath, "started")); err == nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				_ = cmd.Process.Kill()
+				t.Fatalf("hook did not start\nstderr: %s", stderr.String())
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			t.Fatalf("failed to send SIGINT: %v", err)
+		}
+		if err := cmd.Wait(); err == nil {
+			t.Fatal("interrupted git-wt should fail")
+		}
+
+		if !strings.Contains(stderr.String(), "Rolled back worktree") {
+			t.Errorf("stderr should mention the rollback, got: %s", stderr.String())
+		}
+		if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+			t.Error("worktree directory should have been removed")
+		}
+		if strings.Contains(repo.Git("worktree", "list"), wtPath) {
+			t.Error("worktree should not be registered")
+		}
+		if strings.Contains(repo.Git("branch", "--list", "interrupted"), "interrupted") {
+			t.Error("branch created by git-wt should have been deleted")
+		}
+	})
+}
@@ -0,0 +1,223 @@
+// lock_test.go contains worktree lock tests:
+//   - TestE2E_LockWorktree: --lock/--unlock and their effect on -d/-D/-m/-M
+package e2e
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestE2E_LockWorktree(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("lock_and_unlock", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "release")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		out, err = runGitWt(t, binPath, repo.Root, "--lock", "--reason", "long-running release", "release")
+		if err != nil {
+			t.Fatalf("git-wt --lock failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, `Locked worktree "release"`) {
+			t.Errorf("output should confirm the lock, got: %s", out)
+		}
+
+		list := repo.Git("worktree", "list", "--porcelain")
+		if !strings.Contains(list, "locked long-running release") {
+			t.Errorf("worktree should be locked with reason, got:\n%s", list)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--lock", "release")
+		if err == nil {
+			t.Fatal("locking an already locked worktree should fail")
+		}
+		if !strings.Contains(out, "already locked") {
+			t.Errorf("error should mention the worktree is already locked, got: %s", out)
+		}
+
+		// Unlock by path
+		out, err = runGitWt(t, binPath, repo.Root, "--unlock", wtPath)
+		if err != nil {
+			t.Fatalf("git-wt --unlock failed: %v\noutput: %s", err, out)
+		}
+		list = repo.Git("worktree", "list", "--porcelain")
+		if strings.Contains(list, "locked") {
+			t.Errorf("worktree should be unlocked, got:\n%s", list)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--unlock", "release")
+		if err == nil {
+			t.Fatal("unlocking a worktree that is not locked should fail")
+		}
+		if !strings.Contains(out, "is not locked") {
+			t.Errorf("error should mention the worktree is not locked, got: %s", out)
+		}
+	})
+
+	t.Run("delete_refused_unless_forced", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "pinned")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		if out, err := runGitWt(t, binPath, repo.Root, "--lock", "pinned"); err != nil {
+			t.Fatalf("git-wt --lock failed: %v\noutput: %s", err, out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "-d", "pinned")
+		if err == nil {
+			t.Fatal("git-wt -d should refuse a locked worktree")
+		}
+		if !strings.Contains(out, "is locked") || !strings.Contains(out, "-D") {
+			t.Errorf("error should mention the lock and -D, got: %s", out)
+		}
+		assertWorktreeExists(t, wtPath)
+
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "pinned")
+		if err != nil {
+			t.Fatalf("git-wt -D should delete a locked worktree: %v\noutput: %s", err, out)
+		}
+		assertWorktreeDeleted(t, wtPath)
+	})
+
+	t.Run("delete_forced_with_remover", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "pinned-remover")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		if out, err := runGitWt(t, binPath, repo.Root, "--lock", "pinned-remover"); err != nil {
+			t.Fatalf("git-wt --lock failed: %v\noutput: %s", err, out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "--remover", "rm -rf", "pinned-remover")
+		if err != nil {
+			t.Fatalf("git-wt -D --remover should delete a locked worktree: %v\noutput: %s", err, out)
+		}
+		assertWorktreeDeleted(t, wtPath)
+
+		list := repo.Git("worktree", "list", "--porcelain")
+		if strings.Contains(list, wtPath) {
+			t.Errorf("worktree should have been pruned, got:\n%s", list)
+		}
+	})
+
+	t.Run("relocked_when_forced_delete_fails", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "pinned-fail")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		if out, err := runGitWt(t, binPath, repo.Root, "--lock", "--reason", "keep", "pinned-fail"); err != nil {
+			t.Fatalf("git-wt --lock failed: %v\noutput: %s", err, out)
+		}
+
+		if _, err := runGitWt(t, binPath, repo.Root, "-D", "--remover", "exit 1", "pinned-fail"); err == nil {
+			t.Fatal("git-wt -D should fail when the remover fails")
+		}
+		assertWorktreeExists(t, wtPath)
+
+		list := repo.Git("worktree", "list", "--porcelain")
+		if !strings.Contains(list, "locked keep") {
+			t.Errorf("worktree should be locked again with its reason, got:\n%s", list)
+		}
+	})
+
+	t.Run("move_refused_unless_forced", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "pinned-move")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		if out, err := runGitWt(t, binPath, repo.Root, "--lock", "--reason", "keep", "pinned-move"); err != nil {
+			t.Fatalf("git-wt --lock failed: %v\noutput: %s", err, out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "-m", "pinned-move", "renamed")
+		if err == nil {
+			t.Fatal("git-wt -m should refuse a locked worktree")
+		}
+		if !strings.Contains(out, "is locked (reason: keep)") || !strings.Contains(out, "-M") {
+			t.Errorf("error should mention the lock and -M, got: %s", out)
+		}
+		assertWorktreeExists(t, wtPath)
+
+		out, err = runGitWt(t, binPath, repo.Root, "-M", "pinned-move", "renamed")
+		if err != nil {
+			t.Fatalf("git-wt -M should move a locked worktree: %v\noutput: %s", err, out)
+		}
+		newPath := filepath.Join(filepath.Dir(wtPath), "renamed")
+		assertWorktreeExists(t, newPath)
+
+		// The lock is kept after the move
+		list := repo.Git("worktree", "list", "--porcelain")
+		if !strings.Contains(list, "locked keep") {
+			t.Errorf("moved worktree should still be locked, got:\n%s", list)
+		}
+	})
+
+	t.Run("invalid_usage", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		tests := []struct {
+			name string
+			args []string
+			want string
+		}{
+			{"no_args", []string{"--lock"}, "expected at least one"},
+			{"lock_and_unlock", []string{"--lock", "--unlock", "x"}, "cannot combine --lock with --unlock"},
+			{"with_delete", []string{"--lock", "-d", "x"}, "cannot combine --lock/--unlock"},
+			{"reason_without_lock", []string{"--reason", "r", "x"}, "--reason can only be used with --lock"},
+			{"not_found", []string{"--lock", "no-such-worktree"}, "no worktree found"},
+		}
+		for _, tt := range tests {
+			out, err := runGitWt(t, binPath, repo.Root, tt.args...)
+			if err == nil {
+				t.Errorf("%s: git-wt %v should fail", tt.name, tt.args)
+				continue
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("%s: error should contain %q, got: %s", tt.name, tt.want, out)
+			}
+		}
+	})
+}
@@ -0,0 +1,156 @@
+// prune_test.go contains merged worktree pruning tests:
+//   - TestE2E_PruneMerged: --prune-merged deletes merged worktrees and skips the rest
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestE2E_PruneMerged(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("deletes_merged_and_skips_others", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		paths := map[string]string{}
+		for _, b := range []string{"merged", "unmerged", "dirty", "locked", "fresh"} {
+			out, err := runGitWt(t, binPath, repo.Root, b)
+			if err != nil {
+				t.Fatalf("failed to create worktree %s: %v\noutput: %s", b, err, out)
+			}
+			paths[b] = worktreePath(out)
+		}
+
+		// Add a commit on "merged" and fast-forward main to it; "fresh" has
+		// no commits of its own
+		commitUnmergedChange(t, paths["merged"])
+		repo.Git("merge", "--ff-only", "merged")
+
+		// Add a commit on "unmerged" only
+		if err := os.WriteFile(filepath.Join(paths["unmerged"], "feature.txt"), []byte("feature"), 0600); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+		repo.Git("-C", paths["unmerged"], "add", ".")
+		repo.Git("-C", paths["unmerged"], "commit", "-m", "unmerged commit")
+
+		if err := os.WriteFile(filepath.Join(paths["dirty"], "untracked.txt"), []byte("x"), 0600); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+		repo.Git("worktree", "lock", paths["locked"])
+
+		out, err := runGitWt(t, binPath, repo.Root, "--prune-merged")
+		if err != nil {
+			t.Fatalf("git-wt --prune-merged failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, `Skipping "dirty" (has modified or untracked files)`) {
+			t.Errorf("output should report the dirty worktree as skipped, got: %s", out)
+		}
+		if !strings.Contains(out, `Skipping "locked" (locked)`) {
+			t.Errorf("output should report the locked worktree as skipped, got: %s", out)
+		}
+		if !strings.Contains(out, `Skipping "fresh" (no commits since it was created)`) {
+			t.Errorf("output should report the fresh worktree as skipped, got: %s", out)
+		}
+
+		if _, err := os.Stat(paths["merged"]); !os.IsNotExist(err) {
+			t.Errorf("merged worktree should be deleted: %s", paths["merged"])
+		}
+		if strings.Contains(repo.Git("branch", "--list", "merged"), "merged") {
+			t.Error("merged branch should be deleted")
+		}
+		for _, b := range []string{"unmerged", "dirty", "locked", "fresh"} {
+			if _, err := os.Stat(paths[b]); err != nil {
+				t.Errorf("%s worktree should be kept: %v", b, err)
+			}
+		}
+		if !strings.Contains(repo.Git("branch", "--list", "main"), "main") {
+			t.Error("default branch should be kept")
+		}
+	})
+
+	t.Run("deletes_squash_merged", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "squashed")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		commitUnmergedChange(t, wtPath)
+		repo.Git("merge", "--squash", "squashed")
+		repo.Commit("squash merge")
+
+		out, err = runGitWt(t, binPath, repo.Root, "--prune-merged")
+		if err != nil {
+			t.Fatalf("git-wt --prune-merged failed: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+			t.Errorf("squash-merged worktree should be deleted: %s", wtPath)
+		}
+		if strings.Contains(repo.Git("branch", "--list", "squashed"), "squashed") {
+			t.Error("squash-merged branch should be deleted")
+		}
+	})
+
+	t.Run("skips_current_worktree", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "current")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		out, err = runGitWt(t, binPath, wtPath, "--prune-merged")
+		if err != nil {
+			t.Fatalf("git-wt --prune-merged failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, `Skipping "current" (current worktree)`) {
+			t.Errorf("output should report the current worktree as skipped, got: %s", out)
+		}
+		if !strings.Contains(out, "No merged worktrees to delete") {
+			t.Errorf("output should report nothing to delete, got: %s", out)
+		}
+		if _, err := os.Stat(wtPath); err != nil {
+			t.Errorf("current worktree should be kept: %v", err)
+		}
+	})
+
+	t.Run("invalid_usage", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--prune-merged", "feature")
+		if err == nil {
+			t.Fatal("--prune-merged with arguments should fail")
+		}
+		if !strings.Contains(out, "--prune-merged does not take arguments") {
+			t.Errorf("unexpected error, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--prune-merged", "-D")
+		if err == nil {
+			t.Fatal("--prune-merged with -D should fail")
+		}
+		if !strings.Contains(out, "cannot combine --prune-merged") {
+			t.Errorf("unexpected error, got: %s", out)
+		}
+	})
+}
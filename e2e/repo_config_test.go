@@ -0,0 +1,96 @@
+// repo_config_test.go contains committed config file tests:
+//   - TestE2E_RepoConfig: .git-wt hooks run only after the file is trusted
+package e2e
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+// runGitWtWithGlobalConfig runs git-wt with globalConfig as the user's
+// global git config, where approvals of .git-wt files are recorded.
+func runGitWtWithGlobalConfig(t *testing.T, binPath, dir, globalConfig string, args ...string) (stdout string, stderr string, err error) {
+	t.Helper()
+
+	cmd := exec.Command(binPath, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL="+globalConfig)
+	var stdoutBuf, stderrBuf bytes.Buffer
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
+	err = cmd.Run()
+	return strings.TrimSpace(stdoutBuf.String()), strings.TrimSpace(stderrBuf.String()), err
+}
+
+func TestE2E_RepoConfig(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("requires_trust", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".git-wt", "[wt]\n\thook = touch team-hook\n")
+		repo.Commit("initial commit")
+		globalConfig := filepath.Join(t.TempDir(), "gitconfig")
+
+		stdout, stderr, err := runGitWtWithGlobalConfig(t, binPath, repo.Root, globalConfig, "untrusted")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stderr, "is not trusted") {
+			t.Errorf("stderr should warn about the untrusted file, got: %s", stderr)
+		}
+		if _, err := os.Stat(filepath.Join(worktreePath(stdout), "team-hook")); !os.IsNotExist(err) {
+			t.Error("hooks of an untrusted .git-wt should not run")
+		}
+
+		if _, stderr, err := runGitWtWithGlobalConfig(t, binPath, repo.Root, globalConfig, "--trust"); err != nil {
+			t.Fatalf("git-wt --trust failed: %v\nstderr: %s", err, stderr)
+		}
+		stdout, stderr, err = runGitWtWithGlobalConfig(t, binPath, repo.Root, globalConfig, "trusted")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\nstderr: %s", err, stderr)
+		}
+		if strings.Contains(stderr, "is not trusted") {
+			t.Errorf("stderr should not warn after --trust, got: %s", stderr)
+		}
+		if _, err := os.Stat(filepath.Join(worktreePath(stdout), "team-hook")); err != nil {
+			t.Error("hooks of a trusted .git-wt should run")
+		}
+
+		// A changed file needs to be trusted again
+		repo.CreateFile(".git-wt", "[wt]\n\thook = touch changed-hook\n")
+		stdout, stderr, err = runGitWtWithGlobalConfig(t, binPath, repo.Root, globalConfig, "changed")
+		if err != nil {
+			t.Fatalf("git-wt failed: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stderr, "is not trusted") {
+			t.Errorf("stderr should warn about the changed file, got: %s", stderr)
+		}
+		if _, err := os.Stat(filepath.Join(worktreePath(stdout), "changed-hook")); !os.IsNotExist(err) {
+			t.Error("hooks of a changed .git-wt should not run")
+		}
+	})
+
+	t.Run("trust_without_file", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		_, stderr, err := runGitWtWithGlobalConfig(t, binPath, repo.Root, filepath.Join(t.TempDir(), "gitconfig"), "--trust")
+		if err == nil {
+			t.Fatal("--trust should fail without a .git-wt file")
+		}
+		if !strings.Contains(stderr, "no .git-wt file") {
+			t.Errorf("unexpected error: %s", stderr)
+		}
+	})
+}
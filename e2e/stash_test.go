@@ -0,0 +1,192 @@
+// stash_test.go contains stash-on-delete tests:
+//   - TestE2E_DeleteStash: -D --stash / wt.deletestash and --restore-stash
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestE2E_DeleteStash(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("stash_and_restore", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		commitUnmergedChange(t, wtPath)
+		if err := os.WriteFile(filepath.Join(wtPath, "README.md"), []byte("# Modified"), 0600); err != nil {
+			t.Fatalf("failed to modify file: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(wtPath, "wip.txt"), []byte("wip"), 0600); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "--stash", "feature")
+		if err != nil {
+			t.Fatalf("git-wt -D --stash failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, "refs/wt-stash/feature") {
+			t.Errorf("output should mention the stash ref, got: %s", out)
+		}
+		if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+			t.Error("worktree should have been deleted")
+		}
+		if strings.Contains(repo.Git("branch", "--list", "feature"), "feature") {
+			t.Error("branch should have been deleted")
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--restore-stash")
+		if err != nil {
+			t.Fatalf("git-wt --restore-stash failed: %v\noutput: %s", err, out)
+		}
+		if strings.TrimSpace(out) != "feature" {
+			t.Errorf("--restore-stash without arguments should list stashes, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--restore-stash", "feature")
+		if err != nil {
+			t.Fatalf("git-wt --restore-stash feature failed: %v\noutput: %s", err, out)
+		}
+		restoredPath := worktreePath(out)
+		content, err := os.ReadFile(filepath.Join(restoredPath, "README.md"))
+		if err != nil || string(content) != "# Modified" {
+			t.Errorf("README.md = %q, %v; want restored modification", content, err)
+		}
+		if _, err := os.Stat(filepath.Join(restoredPath, "wip.txt")); err != nil {
+			t.Errorf("untracked file should be restored: %v", err)
+		}
+		// The committed change is back with the recreated branch
+		if _, err := os.Stat(filepath.Join(restoredPath, "new.txt")); err != nil {
+			t.Errorf("branch should be recreated at the stashed commit: %v", err)
+		}
+		if refs := repo.Git("for-each-ref", "refs/wt-stash/"); refs != "" {
+			t.Errorf("stash ref should be deleted after restoring, got: %s", refs)
+		}
+	})
+
+	t.Run("restored_when_removal_fails", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "kept")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		if err := os.WriteFile(filepath.Join(wtPath, "wip.txt"), []byte("wip"), 0600); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+
+		// The delete hook still sees the uncommitted changes
+		hook := "test -f wip.txt && touch ../hook-saw-wip"
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "--stash", "--deletehook", hook, "--remover", "exit 1", "kept")
+		if err == nil {
+			t.Fatalf("git-wt -D should fail when the remover fails, output: %s", out)
+		}
+		if _, err := os.Stat(filepath.Join(filepath.Dir(wtPath), "hook-saw-wip")); err != nil {
+			t.Error("delete hooks should run before the changes are stashed")
+		}
+		if !strings.Contains(out, `Restored uncommitted changes of "kept"`) {
+			t.Errorf("output should report the restored changes, got: %s", out)
+		}
+		content, err := os.ReadFile(filepath.Join(wtPath, "wip.txt"))
+		if err != nil || string(content) != "wip" {
+			t.Errorf("wip.txt = %q, %v; want the changes back in the worktree", content, err)
+		}
+		if refs := repo.Git("for-each-ref", "refs/wt-stash/"); refs != "" {
+			t.Errorf("stash ref should be deleted after applying it back, got: %s", refs)
+		}
+	})
+
+	t.Run("config_deletestash", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.deletestash", "true")
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		if err := os.WriteFile(filepath.Join(wtPath, "wip.txt"), []byte("wip"), 0600); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "feature")
+		if err != nil {
+			t.Fatalf("git-wt -D failed: %v\noutput: %s", err, out)
+		}
+		if refs := repo.Git("for-each-ref", "refs/wt-stash/"); !strings.Contains(refs, "refs/wt-stash/feature") {
+			t.Errorf("changes should be stashed with wt.deletestash, got refs: %s", refs)
+		}
+
+		// A clean worktree is deleted without a stash
+		out, err = runGitWt(t, binPath, repo.Root, "clean")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "clean")
+		if err != nil {
+			t.Fatalf("git-wt -D failed: %v\noutput: %s", err, out)
+		}
+		if refs := repo.Git("for-each-ref", "refs/wt-stash/"); strings.Contains(refs, "refs/wt-stash/clean") {
+			t.Errorf("clean worktree should not be stashed, got refs: %s", refs)
+		}
+
+		// --stash=false overrides the config
+		out, err = runGitWt(t, binPath, repo.Root, "other")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		if err := os.WriteFile(filepath.Join(worktreePath(out), "wip.txt"), []byte("wip"), 0600); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "--stash=false", "other")
+		if err != nil {
+			t.Fatalf("git-wt -D failed: %v\noutput: %s", err, out)
+		}
+		if refs := repo.Git("for-each-ref", "refs/wt-stash/"); strings.Contains(refs, "refs/wt-stash/other") {
+			t.Errorf("--stash=false should disable stashing, got refs: %s", refs)
+		}
+	})
+
+	t.Run("invalid_usage", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--stash", "feature")
+		if err == nil {
+			t.Fatal("--stash without -d/-D should fail")
+		}
+		if !strings.Contains(out, "--stash can only be used with") {
+			t.Errorf("unexpected error, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--restore-stash", "missing")
+		if err == nil {
+			t.Fatal("--restore-stash without a saved stash should fail")
+		}
+		if !strings.Contains(out, `no stash found for "missing"`) {
+			t.Errorf("unexpected error, got: %s", out)
+		}
+	})
+}
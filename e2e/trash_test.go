@@ -0,0 +1,192 @@
+// trash_test.go contains soft-delete tests:
+//   - TestE2E_Trash: --trash / wt.trash, --restore, --purge-trash and wt.trashexpiry
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestE2E_Trash(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("trash_and_restore", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "experiment")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		commitUnmergedChange(t, wtPath)
+		if err := os.WriteFile(filepath.Join(wtPath, "notes.txt"), []byte("notes"), 0600); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "--trash", "experiment")
+		if err != nil {
+			t.Fatalf("git-wt -D --trash failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, `Moved worktree "experiment" to trash`) {
+			t.Errorf("output should mention the trash, got: %s", out)
+		}
+		if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+			t.Error("worktree directory should have been moved")
+		}
+		if strings.Contains(repo.Git("branch", "--list", "experiment"), "experiment") {
+			t.Error("branch should have been deleted")
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--restore")
+		if err != nil {
+			t.Fatalf("git-wt --restore failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, "experiment") || !strings.Contains(out, wtPath) {
+			t.Errorf("--restore without arguments should list the trash, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--restore", "experiment")
+		if err != nil {
+			t.Fatalf("git-wt --restore experiment failed: %v\noutput: %s", err, out)
+		}
+		if worktreePath(out) != wtPath {
+			t.Errorf("--restore should print the restored path %s, got: %s", wtPath, out)
+		}
+		for _, f := range []string{"new.txt", "notes.txt"} {
+			if _, err := os.Stat(filepath.Join(wtPath, f)); err != nil {
+				t.Errorf("%s should be restored: %v", f, err)
+			}
+		}
+		if !strings.Contains(repo.Git("worktree", "list"), "[experiment]") {
+			t.Error("worktree should be registered with its branch again")
+		}
+
+		// The restored worktree can be deleted normally
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "experiment")
+		if err != nil {
+			t.Fatalf("git-wt -D failed after restore: %v\noutput: %s", err, out)
+		}
+	})
+
+	t.Run("trash_current_worktree", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.trash", "true")
+
+		out, err := runGitWt(t, binPath, repo.Root, "current")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		out, err = runGitWt(t, binPath, wtPath, "-d", "current")
+		if err != nil {
+			t.Fatalf("git-wt -d from inside the worktree failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, `Deleted worktree and branch "current"`) {
+			t.Errorf("branch should be deleted, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--restore", "current")
+		if err != nil {
+			t.Fatalf("git-wt --restore failed: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(wtPath); err != nil {
+			t.Errorf("worktree should be restored: %v", err)
+		}
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.trash", "true")
+
+		for _, b := range []string{"first", "second"} {
+			out, err := runGitWt(t, binPath, repo.Root, b)
+			if err != nil {
+				t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+			}
+			out, err = runGitWt(t, binPath, repo.Root, "-d", b)
+			if err != nil {
+				t.Fatalf("git-wt -d failed: %v\noutput: %s", err, out)
+			}
+		}
+
+		out, err := runGitWt(t, binPath, repo.Root, "--purge-trash", "first")
+		if err != nil {
+			t.Fatalf("git-wt --purge-trash first failed: %v\noutput: %s", err, out)
+		}
+		out, err = runGitWt(t, binPath, repo.Root, "--restore")
+		if err != nil {
+			t.Fatalf("git-wt --restore failed: %v\noutput: %s", err, out)
+		}
+		if strings.Contains(out, "first") || !strings.Contains(out, "second") {
+			t.Errorf("only the named entry should be purged, got: %s", out)
+		}
+
+		// Expired entries are purged explicitly and after each deletion
+		repo.Git("config", "wt.trashexpiry", "1ns")
+		out, err = runGitWt(t, binPath, repo.Root, "--purge-trash")
+		if err != nil {
+			t.Fatalf("git-wt --purge-trash failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, "second") || !strings.Contains(out, "(expired)") {
+			t.Errorf("expired entry should be purged, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "third")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		out, err = runGitWt(t, binPath, repo.Root, "-d", "third")
+		if err != nil {
+			t.Fatalf("git-wt -d failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, "(expired)") {
+			t.Errorf("deletion should purge expired entries, got: %s", out)
+		}
+		if refs := repo.Git("for-each-ref", "refs/wt-trash/"); refs != "" {
+			t.Errorf("trash refs should be purged, got: %s", refs)
+		}
+	})
+
+	t.Run("invalid_usage", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--trash", "feature")
+		if err == nil {
+			t.Fatal("--trash without -d/-D should fail")
+		}
+		if !strings.Contains(out, "--trash can only be used with") {
+			t.Errorf("unexpected error, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--restore", "missing")
+		if err == nil {
+			t.Fatal("--restore of an unknown name should fail")
+		}
+		if !strings.Contains(out, `no worktree named "missing" in the trash`) {
+			t.Errorf("unexpected error, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--restore", "--purge-trash")
+		if err == nil {
+			t.Fatal("--restore with --purge-trash should fail")
+		}
+	})
+}
@@ -1,10 +1,15 @@
 package git
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/k1LoW/exec"
 )
 
 const gitDefaultBranch = "master"
@@ -128,28 +133,224 @@ func RenameBranch(ctx context.Context, oldName, newName string, force bool, dir
 	return cmd.Run()
 }
 
-// IsBranchMerged checks if a branch is merged into the current branch.
+// IsBranchMerged reports whether a branch is merged into the default branch
+// (see DefaultBranchRef), regardless of what is checked out. In addition to
+// regular merges and fast-forwards, it recognizes branches that were
+// rebase-merged (every commit has an equivalent patch on the default branch)
+// or squash-merged (the combined diff of the branch has an equivalent patch
+// on the default branch).
 func IsBranchMerged(ctx context.Context, name string) (bool, error) {
-	cmd, err := gitCommand(ctx, "branch", "--merged")
+	target, err := DefaultBranchRef(ctx)
 	if err != nil {
 		return false, err
 	}
-	out, err := cmd.Output()
+	return IsBranchMergedInto(ctx, name, target)
+}
+
+// IsBranchMergedInto is like IsBranchMerged but checks against the given
+// target ref instead of the default branch.
+func IsBranchMergedInto(ctx context.Context, name, target string) (bool, error) {
+	// Regular merge or fast-forward
+	cmd, err := gitCommand(ctx, "merge-base", "--is-ancestor", "refs/heads/"+name, target)
 	if err != nil {
 		return false, err
 	}
+	if err := cmd.Run(); err == nil {
+		return true, nil
+	}
 
-	lines := strings.Split(string(out), "\n")
-	for _, line := range lines {
-		// Remove leading * and spaces
-		branch := strings.TrimSpace(strings.TrimPrefix(line, "*"))
-		if branch == name {
+	cmd, err = gitCommand(ctx, "merge-base", target, "refs/heads/"+name)
+	if err != nil {
+		return false, err
+	}
+	b, err := cmd.Output()
+	mergeBase := strings.TrimSpace(string(b))
+	if err != nil {
+		// git merge-base exits with 1 and prints nothing if there is no
+		// common history (e.g., an orphan branch)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && mergeBase == "" {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to find merge base of %s and %s: %w", name, target, err)
+	}
+
+	// Rebase merge: 'git cherry' prefixes commits with an equivalent
+	// change upstream with "-" and the others with "+".
+	out, err := gitOutput(ctx, nil, "cherry", target, "refs/heads/"+name, mergeBase)
+	if err != nil {
+		return false, err
+	}
+	if !strings.Contains(out, "+") {
+		return true, nil
+	}
+
+	// Squash merge: compare the patch-id of the combined diff of the branch
+	// with the patch-ids of the target's commits since the merge base. This
+	// only reads the repository.
+	var squashed string
+	if err := eachPatchID(ctx, func(id string) bool {
+		squashed = id
+		return false
+	}, "diff-tree", "-p", "--no-color", mergeBase, "refs/heads/"+name); err != nil {
+		return false, err
+	}
+	if squashed == "" {
+		// The branch changes nothing overall
+		return false, nil
+	}
+	var merged bool
+	if err := eachPatchID(ctx, func(id string) bool {
+		merged = id == squashed
+		return !merged
+	}, "log", "-p", "--no-merges", "--no-renames", "--no-ext-diff", "--no-textconv", "--no-color",
+		"--src-prefix=a/", "--dst-prefix=b/", "--format=commit %H", mergeBase+".."+target); err != nil {
+		return false, err
+	}
+	return merged, nil
+}
+
+// eachPatchID pipes the patches printed by git with args into 'git patch-id
+// --stable' and calls fn with the patch-id of each of them, until fn returns
+// false. The patches are streamed from one process to the other.
+func eachPatchID(ctx context.Context, fn func(id string) bool, args ...string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	src, err := gitCommand(ctx, args...)
+	if err != nil {
+		return err
+	}
+	patchID, err := gitCommand(ctx, "patch-id", "--stable")
+	if err != nil {
+		return err
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	src.Stdout = w
+	patchID.Stdin = r
+	stdout, err := patchID.StdoutPipe()
+	if err != nil {
+		_ = r.Close() //nostyle:handlerrors
+		_ = w.Close() //nostyle:handlerrors
+		return err
+	}
+	if err := patchID.Start(); err != nil {
+		_ = r.Close() //nostyle:handlerrors
+		_ = w.Close() //nostyle:handlerrors
+		return fmt.Errorf("failed to run git patch-id: %w", err)
+	}
+	// The child processes hold their own ends of the pipe
+	_ = r.Close() //nostyle:handlerrors
+	if err := src.Start(); err != nil {
+		_ = w.Close() //nostyle:handlerrors
+		cancel()
+		_ = patchID.Wait() //nostyle:handlerrors
+		return fmt.Errorf("failed to run git %s: %w", args[0], err)
+	}
+	_ = w.Close() //nostyle:handlerrors
+
+	stopped := false
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		// Each line is "<patch-id> <commit-id>"
+		id, _, _ := strings.Cut(scanner.Text(), " ")
+		if !fn(id) {
+			stopped = true
+			break
+		}
+	}
+	serr := scanner.Err()
+	if stopped {
+		// The rest of the output is not needed
+		cancel()
+	}
+	srcErr := src.Wait()
+	patchIDErr := patchID.Wait()
+	if stopped {
+		return nil
+	}
+	if srcErr != nil {
+		return fmt.Errorf("failed to run git %s: %w", args[0], srcErr)
+	}
+	if patchIDErr != nil {
+		return fmt.Errorf("failed to run git patch-id: %w", patchIDErr)
+	}
+	return serr
+}
+
+// BranchHasOwnCommits reports whether a local branch has commits of its own
+// relative to target. A branch whose tip is not reachable from target has.
+// Otherwise its tip is the merge base, which a fast-forward merged branch and
+// a branch that was just created from target have in common, so the branch's
+// reflog tells them apart: it has commits of its own if it ever pointed to
+// another commit. Without a reflog (core.logAllRefUpdates is off), it
+// reports false.
+func BranchHasOwnCommits(ctx context.Context, name, target string) (bool, error) {
+	cmd, err := gitCommand(ctx, "merge-base", "--is-ancestor", "refs/heads/"+name, target)
+	if err != nil {
+		return false, err
+	}
+	if err := cmd.Run(); err != nil {
+		return true, nil //nostyle:handlerrors
+	}
+
+	tip, err := gitOutput(ctx, nil, "rev-parse", "refs/heads/"+name)
+	if err != nil {
+		return false, err
+	}
+	reflog, err := gitOutput(ctx, nil, "reflog", "show", "--format=%H", "refs/heads/"+name, "--")
+	if err != nil {
+		return false, err
+	}
+	for line := range strings.Lines(reflog) {
+		if strings.TrimSpace(line) != tip {
 			return true, nil
 		}
 	}
 	return false, nil
 }
 
+// UnpushedCommits returns the commits on a local branch that are not on its
+// upstream, one "<short hash> <subject>" entry per commit (newest first).
+// It returns nil when the branch has no upstream or the upstream ref no
+// longer exists (e.g., the remote branch was deleted after merging).
+func UnpushedCommits(ctx context.Context, name string) ([]string, error) {
+	upstream := name + "@{upstream}"
+	cmd, err := gitCommand(ctx, "rev-parse", "--verify", "--quiet", upstream)
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Run(); err != nil {
+		return nil, nil //nostyle:handlerrors
+	}
+
+	out, err := gitOutput(ctx, nil, "log", "--format=%h %s", upstream+"..refs/heads/"+name)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// gitOutput runs git with the given stdin and returns its trimmed stdout.
+func gitOutput(ctx context.Context, stdin io.Reader, args ...string) (string, error) {
+	cmd, err := gitCommand(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	cmd.Stdin = stdin
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // ListBranches returns a list of all local branch names.
 func ListBranches(ctx context.Context) ([]string, error) {
 	cmd, err := gitCommand(ctx, "branch", "--format=%(refname:short)")
@@ -244,6 +445,31 @@ func DefaultBranch(ctx context.Context) (string, error) {
 	return configBranch, nil
 }
 
+// DefaultBranchRef returns a ref for the default branch that can be used as a
+// merge target. It prefers the local branch and falls back to the
+// remote-tracking branch on origin when no local branch exists.
+func DefaultBranchRef(ctx context.Context) (string, error) {
+	defaultBranch, err := DefaultBranch(ctx)
+	if err != nil {
+		return "", err
+	}
+	exists, err := LocalBranchExists(ctx, defaultBranch)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return defaultBranch, nil
+	}
+	cmd, err := gitCommand(ctx, "show-ref", "--verify", "--quiet", "refs/remotes/origin/"+defaultBranch)
+	if err != nil {
+		return "", err
+	}
+	if err := cmd.Run(); err == nil {
+		return "origin/" + defaultBranch, nil
+	}
+	return "", fmt.Errorf("default branch %q not found locally or on origin", defaultBranch)
+}
+
 // HeadBranch returns the branch name that HEAD points to.
 // Returns an error if HEAD is detached or not a symbolic ref.
 func HeadBranch(ctx context.Context) (string, error) {
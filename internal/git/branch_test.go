@@ -252,22 +252,57 @@ func TestIsBranchMerged(t *testing.T) {
 	repo.Git("checkout", "main")
 	repo.Git("merge", "merged-branch")
 
-	// Create an unmerged branch
+	// Create a squash-merged branch
+	repo.Git("checkout", "-b", "squashed-branch")
+	repo.CreateFile("squash1.txt", "squash 1")
+	repo.Commit("first commit on squashed branch")
+	repo.CreateFile("squash2.txt", "squash 2")
+	repo.Commit("second commit on squashed branch")
+	repo.Git("checkout", "main")
+	repo.Git("merge", "--squash", "squashed-branch")
+	repo.Commit("squash merge")
+
+	// Create a rebase-merged branch (commits replayed onto main)
+	repo.Git("checkout", "-b", "rebased-branch", "main~1")
+	repo.CreateFile("rebase1.txt", "rebase 1")
+	repo.Commit("first commit on rebased branch")
+	repo.CreateFile("rebase2.txt", "rebase 2")
+	repo.Commit("second commit on rebased branch")
+	repo.Git("checkout", "main")
+	repo.Git("cherry-pick", "main..rebased-branch")
+
+	// Create an unmerged branch and a partially merged one
 	repo.Git("checkout", "-b", "unmerged-branch")
 	repo.CreateFile("unmerged.txt", "unmerged content")
 	repo.Commit("commit on unmerged branch")
-	repo.Git("checkout", "main")
+	repo.Git("checkout", "-b", "partial-branch", "rebased-branch")
+	repo.CreateFile("partial.txt", "partial content")
+	repo.Commit("commit on partial branch")
+
+	// Create a branch without common history
+	repo.Git("checkout", "--orphan", "orphan-branch")
+	repo.CreateFile("orphan.txt", "orphan content")
+	repo.Commit("commit on orphan branch")
+
+	// Stay on the unmerged branch: the result must not depend on HEAD
+	repo.Git("checkout", "unmerged-branch")
 
 	restore := repo.Chdir()
 	defer restore()
 
+	objects := repo.Git("count-objects", "-v")
+
 	tests := []struct {
 		name   string
 		branch string
 		want   bool
 	}{
 		{"merged branch", "merged-branch", true},
+		{"squash-merged branch", "squashed-branch", true},
+		{"rebase-merged branch", "rebased-branch", true},
 		{"unmerged branch", "unmerged-branch", false},
+		{"partially merged branch", "partial-branch", false},
+		{"branch without common history", "orphan-branch", false},
 		{"main branch", "main", true},
 	}
 
@@ -282,6 +317,61 @@ func TestIsBranchMerged(t *testing.T) {
 			}
 		})
 	}
+
+	// Checking is read-only
+	if got := repo.Git("count-objects", "-v"); got != objects {
+		t.Errorf("IsBranchMerged should not write objects\nbefore: %s\nafter: %s", objects, got)
+	}
+}
+
+func TestBranchHasOwnCommits(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	// Created from main and never committed to
+	repo.Git("branch", "fresh-branch")
+
+	// Committed to, then fast-forward merged
+	repo.Git("checkout", "-b", "ff-branch")
+	repo.CreateFile("ff.txt", "ff content")
+	repo.Commit("commit on ff branch")
+	repo.Git("checkout", "main")
+	repo.Git("merge", "--ff-only", "ff-branch")
+
+	// Committed to, not merged
+	repo.Git("checkout", "-b", "unmerged-branch")
+	repo.CreateFile("unmerged.txt", "unmerged content")
+	repo.Commit("commit on unmerged branch")
+	repo.Git("checkout", "main")
+
+	// Created without a reflog
+	repo.Git("-c", "core.logAllRefUpdates=false", "branch", "nolog-branch", "main~1")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	tests := []struct {
+		branch string
+		want   bool
+	}{
+		{"fresh-branch", false},
+		{"ff-branch", true},
+		{"unmerged-branch", true},
+		{"nolog-branch", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			got, err := BranchHasOwnCommits(t.Context(), tt.branch, "main")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("BranchHasOwnCommits(%q) = %v, want %v", tt.branch, got, tt.want)
+			}
+		})
+	}
 }
 
 func TestDefaultBranch(t *testing.T) {
@@ -304,3 +394,81 @@ func TestDefaultBranch(t *testing.T) {
 		t.Errorf("DefaultBranch() = %q, want %q", branch, "main")
 	}
 }
+
+func TestDefaultBranchRef(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("config", "init.defaultBranch", "main")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	ref, err := DefaultBranchRef(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ref != "main" {
+		t.Errorf("DefaultBranchRef() = %q, want %q", ref, "main")
+	}
+
+	// Without a local default branch, fall back to origin/<default>
+	repo.Git("update-ref", "refs/remotes/origin/main", "main")
+	repo.Git("checkout", "-b", "other")
+	repo.Git("branch", "-D", "main")
+
+	ref, err = DefaultBranchRef(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ref != "origin/main" {
+		t.Errorf("DefaultBranchRef() = %q, want %q", ref, "origin/main")
+	}
+}
+
+func TestUnpushedCommits(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("branch", "pushed")
+	repo.Git("checkout", "-b", "feature")
+	repo.CreateFile("a.txt", "a")
+	repo.Commit("first unpushed")
+	repo.CreateFile("b.txt", "b")
+	repo.Commit("second unpushed")
+	repo.Git("checkout", "main")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	// No upstream
+	commits, err := UnpushedCommits(t.Context(), "feature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commits) != 0 {
+		t.Errorf("UnpushedCommits() without upstream = %v, want none", commits)
+	}
+
+	repo.Git("branch", "--set-upstream-to=pushed", "feature")
+	commits, err = UnpushedCommits(t.Context(), "feature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("UnpushedCommits() = %v, want 2 commits", commits)
+	}
+	if !strings.HasSuffix(commits[0], " second unpushed") || !strings.HasSuffix(commits[1], " first unpushed") {
+		t.Errorf("UnpushedCommits() = %v, want newest first with subjects", commits)
+	}
+
+	// Upstream is gone
+	repo.Git("branch", "-D", "pushed")
+	commits, err = UnpushedCommits(t.Context(), "feature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commits) != 0 {
+		t.Errorf("UnpushedCommits() with gone upstream = %v, want none", commits)
+	}
+}
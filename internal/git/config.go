@@ -1,10 +1,15 @@
 package git
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/k1LoW/exec"
@@ -17,28 +22,73 @@ const (
 	configKeyCopyModified  = "wt.copymodified"
 	configKeyNoCopy        = "wt.nocopy"
 	configKeyCopy          = "wt.copy"
+	configKeyCopyMode      = "wt.copymode"
+	configKeyCopyJobs      = "wt.copyjobs"
+	configKeyCopyStrict    = "wt.copystrict"
+	configKeyCopyFrom      = "wt.copyfrom"
 	configKeyHook          = "wt.hook"
 	configKeyDeleteHook    = "wt.deletehook"
+	configKeyPreCreateHook = "wt.precreatehook"
+	configKeySwitchHook    = "wt.switchhook"
+	configKeyMoveHook      = "wt.movehook"
 	configKeyRemover       = "wt.remover"
 	configKeySymlink       = "wt.symlink"
+	configKeyHardlink      = "wt.hardlink"
 	configKeyNoCd          = "wt.nocd"
 	configKeyRelative      = "wt.relative"
+	configKeyDeleteStash   = "wt.deletestash"
+	configKeyTrash         = "wt.trash"
+	configKeyTrashExpiry   = "wt.trashexpiry"
+	configKeyRollback      = "wt.rollback"
+	configKeyHookTimeout   = "wt.hooktimeout"
+	configKeyHookFailure   = "wt.hookfailure"
 )
 
 // Config holds all wt configuration values.
 type Config struct {
-	BaseDir       string
-	CopyIgnored   bool
-	CopyUntracked bool
-	CopyModified  bool
-	NoCopy        []string
-	Copy          []string
-	Symlink       []string
-	Hooks         []string
-	DeleteHooks   []string
-	Remover       string
-	NoCd          bool
-	Relative      bool
+	BaseDir        string
+	CopyIgnored    bool
+	CopyUntracked  bool
+	CopyModified   bool
+	NoCopy         []string
+	Copy           []string
+	Symlink        []string
+	Hardlink       []string
+	CopyMode       string // Validated by PlanCopy; empty means auto
+	CopyJobs       int    // 0 means runtime.NumCPU()
+	CopyStrict     bool
+	CopyFrom       string // Worktree to copy files from; empty means the current worktree
+	Hooks          []string
+	DeleteHooks    []string
+	PreCreateHooks []string
+	SwitchHooks    []string
+	MoveHooks      []string
+	Remover        string
+	NoCd           bool
+	Relative       bool
+	DeleteStash    bool
+	Trash          bool
+	TrashExpiry    string
+	Rollback       bool
+	HookTimeout    string // Parsed by HookOptions; empty means no timeout
+	HookFailure    string // Parsed by HookOptions; empty means abort
+
+	// Path of a .git-wt file that was ignored because it is not trusted
+	UntrustedRepoConfig string
+
+	// Sections scoped to branch patterns (wt "<pattern>".<key>), merged by
+	// ForBranch
+	scopes []configScope
+}
+
+// scopedConfigKeys are the keys that can be scoped to a branch pattern with
+// a wt "<pattern>" section. Their values are added to the global ones.
+var scopedConfigKeys = []string{"hook", "deletehook", "precreatehook", "switchhook", "movehook", "copy", "nocopy", "symlink", "hardlink"}
+
+// configScope holds the values of a wt "<pattern>" section.
+type configScope struct {
+	pattern string
+	values  map[string][]string // Keyed by scopedConfigKeys
 }
 
 // GitConfig retrieves all git config values for a key.
@@ -120,6 +170,51 @@ func LoadConfig(ctx context.Context) (Config, error) {
 	}
 	cfg.Symlink = symlinkPatterns
 
+	// Hardlink
+	hardlinkPatterns, err := GitConfig(ctx, configKeyHardlink)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Hardlink = hardlinkPatterns
+
+	// CopyMode
+	val, err = GitConfig(ctx, configKeyCopyMode)
+	if err != nil {
+		return cfg, err
+	}
+	if len(val) > 0 {
+		cfg.CopyMode = val[len(val)-1]
+	}
+
+	// CopyJobs
+	val, err = GitConfig(ctx, configKeyCopyJobs)
+	if err != nil {
+		return cfg, err
+	}
+	if len(val) > 0 {
+		jobs, err := strconv.Atoi(val[len(val)-1])
+		if err != nil || jobs < 1 {
+			return cfg, fmt.Errorf("invalid %s %q: must be a positive number", configKeyCopyJobs, val[len(val)-1])
+		}
+		cfg.CopyJobs = jobs
+	}
+
+	// CopyStrict
+	val, err = GitConfig(ctx, configKeyCopyStrict)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.CopyStrict = len(val) > 0 && val[len(val)-1] == "true"
+
+	// CopyFrom
+	val, err = GitConfig(ctx, configKeyCopyFrom)
+	if err != nil {
+		return cfg, err
+	}
+	if len(val) > 0 {
+		cfg.CopyFrom = val[len(val)-1]
+	}
+
 	// Hooks
 	hooks, err := GitConfig(ctx, configKeyHook)
 	if err != nil {
@@ -134,6 +229,27 @@ func LoadConfig(ctx context.Context) (Config, error) {
 	}
 	cfg.DeleteHooks = deleteHooks
 
+	// PreCreateHooks
+	preCreateHooks, err := GitConfig(ctx, configKeyPreCreateHook)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.PreCreateHooks = preCreateHooks
+
+	// SwitchHooks
+	switchHooks, err := GitConfig(ctx, configKeySwitchHook)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.SwitchHooks = switchHooks
+
+	// MoveHooks
+	moveHooks, err := GitConfig(ctx, configKeyMoveHook)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.MoveHooks = moveHooks
+
 	// Remover
 	remover, err := GitConfig(ctx, configKeyRemover)
 	if err != nil {
@@ -157,9 +273,206 @@ func LoadConfig(ctx context.Context) (Config, error) {
 	}
 	cfg.Relative = len(val) > 0 && val[len(val)-1] == "true"
 
+	// DeleteStash
+	val, err = GitConfig(ctx, configKeyDeleteStash)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.DeleteStash = len(val) > 0 && val[len(val)-1] == "true"
+
+	// Trash
+	val, err = GitConfig(ctx, configKeyTrash)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Trash = len(val) > 0 && val[len(val)-1] == "true"
+
+	// TrashExpiry
+	val, err = GitConfig(ctx, configKeyTrashExpiry)
+	if err != nil {
+		return cfg, err
+	}
+	if len(val) == 0 {
+		cfg.TrashExpiry = "30d"
+	} else {
+		cfg.TrashExpiry = val[len(val)-1]
+	}
+
+	// Rollback
+	val, err = GitConfig(ctx, configKeyRollback)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Rollback = len(val) > 0 && val[len(val)-1] == "true"
+
+	// HookTimeout
+	val, err = GitConfig(ctx, configKeyHookTimeout)
+	if err != nil {
+		return cfg, err
+	}
+	if len(val) > 0 {
+		cfg.HookTimeout = val[len(val)-1]
+	}
+
+	// HookFailure
+	val, err = GitConfig(ctx, configKeyHookFailure)
+	if err != nil {
+		return cfg, err
+	}
+	if len(val) > 0 {
+		cfg.HookFailure = val[len(val)-1]
+	}
+
+	// Branch-scoped sections
+	cfg.scopes, err = loadConfigScopes(ctx)
+	if err != nil {
+		return cfg, err
+	}
+
+	// Committed .git-wt file, under the values from git config
+	if err := loadRepoConfig(ctx, &cfg); err != nil {
+		return cfg, err
+	}
+
 	return cfg, nil
 }
 
+// loadConfigScopes reads the wt "<pattern>" sections, in config order.
+func loadConfigScopes(ctx context.Context) ([]configScope, error) {
+	keyRegexp := `^wt\..+\.(` + strings.Join(scopedConfigKeys, "|") + `)$`
+	entries, err := configRegexp(ctx, nil, keyRegexp)
+	if err != nil {
+		return nil, err
+	}
+	var scopes []configScope
+	for _, e := range entries {
+		// The pattern is the subsection between "wt." and the last "."
+		i := strings.LastIndex(e.key, ".")
+		scopes = appendScopeValue(scopes, e.key[len("wt."):i], e.key[i+1:], e.value)
+	}
+	return scopes, nil
+}
+
+// appendScopeValue adds a value to the scope of pattern, creating the scope
+// after the existing ones if needed.
+func appendScopeValue(scopes []configScope, pattern, name, value string) []configScope {
+	for i := range scopes {
+		if scopes[i].pattern == pattern {
+			scopes[i].values[name] = append(scopes[i].values[name], value)
+			return scopes
+		}
+	}
+	return append(scopes, configScope{pattern: pattern, values: map[string][]string{name: {value}}})
+}
+
+// configEntry is a key/value pair read with git config --get-regexp.
+type configEntry struct {
+	key   string
+	value string
+}
+
+// configRegexp returns the config entries whose key matches keyRegexp, in
+// config order. When file is not nil, only the config in file is read.
+func configRegexp(ctx context.Context, file []byte, keyRegexp string) ([]configEntry, error) {
+	args := []string{"config", "-z"}
+	if file != nil {
+		args = append(args, "--file", "-")
+	}
+	cmd, err := gitCommand(ctx, append(args, "--get-regexp", keyRegexp)...)
+	if err != nil {
+		return nil, err
+	}
+	if file != nil {
+		cmd.Stdin = bytes.NewReader(file)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		// git config returns exit code 1 if no key matches
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []configEntry
+	// With -z, each entry is "<key>\n<value>\0"
+	for entry := range strings.SplitSeq(strings.TrimSuffix(string(out), "\x00"), "\x00") {
+		key, value, _ := strings.Cut(entry, "\n")
+		entries = append(entries, configEntry{key: key, value: value})
+	}
+	return entries, nil
+}
+
+// ForBranch returns c with the values of the wt "<pattern>" sections whose
+// pattern matches branch added to the global ones, e.g. the hooks of
+// wt "frontend/*".hook run after the wt.hook hooks for branch frontend/login.
+// Patterns use the syntax of git's includeIf "onbranch:": "*" does not match
+// "/", "**" matches across "/", and a trailing "/" matches everything below.
+func (c Config) ForBranch(branch string) Config {
+	scopes := c.scopes
+	c.scopes = nil
+	if branch == "" {
+		return c
+	}
+	for _, scope := range scopes {
+		if !MatchBranchPattern(scope.pattern, branch) {
+			continue
+		}
+		v := scope.values
+		c.Hooks = appendScoped(c.Hooks, v["hook"])
+		c.DeleteHooks = appendScoped(c.DeleteHooks, v["deletehook"])
+		c.PreCreateHooks = appendScoped(c.PreCreateHooks, v["precreatehook"])
+		c.SwitchHooks = appendScoped(c.SwitchHooks, v["switchhook"])
+		c.MoveHooks = appendScoped(c.MoveHooks, v["movehook"])
+		c.Copy = appendScoped(c.Copy, v["copy"])
+		c.NoCopy = appendScoped(c.NoCopy, v["nocopy"])
+		c.Symlink = appendScoped(c.Symlink, v["symlink"])
+		c.Hardlink = appendScoped(c.Hardlink, v["hardlink"])
+	}
+	return c
+}
+
+// appendScoped appends scoped values to a copy of global, so that configs
+// returned by ForBranch do not share backing arrays.
+func appendScoped(global, scoped []string) []string {
+	if len(scoped) == 0 {
+		return global
+	}
+	return append(slices.Clip(global), scoped...)
+}
+
+// MatchBranchPattern reports whether branch matches a branch pattern of a
+// wt "<pattern>" section.
+func MatchBranchPattern(pattern, branch string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(branch, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		// Match zero or more segments
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], name[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
+
 // expandTemplate expands template variables in a string.
 // Supported variables:
 //   - {gitroot}: repository root directory name
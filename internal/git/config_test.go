@@ -3,6 +3,7 @@ package git
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/k1LoW/git-wt/testutil"
@@ -115,9 +116,29 @@ func TestLoadConfig(t *testing.T) {
 	if cfg.NoCd {
 		t.Errorf("LoadConfig().NoCd default = %v, want false", cfg.NoCd)
 	}
+	if cfg.DeleteStash {
+		t.Errorf("LoadConfig().DeleteStash default = %v, want false", cfg.DeleteStash)
+	}
+	if cfg.Trash {
+		t.Errorf("LoadConfig().Trash default = %v, want false", cfg.Trash)
+	}
+	if cfg.TrashExpiry != "30d" {
+		t.Errorf("LoadConfig().TrashExpiry default = %q, want %q", cfg.TrashExpiry, "30d")
+	}
+	if cfg.Rollback {
+		t.Errorf("LoadConfig().Rollback default = %v, want false", cfg.Rollback)
+	}
 
-	// Test NoCd setting
+	if cfg.HookTimeout != "" || cfg.HookFailure != "" {
+		t.Errorf("LoadConfig() hook options default = %q, %q, want empty", cfg.HookTimeout, cfg.HookFailure)
+	}
+
+	// Test NoCd, DeleteStash, Rollback and hook option settings
 	repo.Git("config", "wt.nocd", "true")
+	repo.Git("config", "wt.deletestash", "true")
+	repo.Git("config", "wt.rollback", "true")
+	repo.Git("config", "wt.hooktimeout", "5m")
+	repo.Git("config", "wt.hookfailure", "warn")
 
 	cfg, err = LoadConfig(t.Context())
 	if err != nil {
@@ -127,6 +148,134 @@ func TestLoadConfig(t *testing.T) {
 	if !cfg.NoCd {
 		t.Errorf("LoadConfig().NoCd = %v, want true", cfg.NoCd)
 	}
+	if !cfg.DeleteStash {
+		t.Errorf("LoadConfig().DeleteStash = %v, want true", cfg.DeleteStash)
+	}
+	if !cfg.Rollback {
+		t.Errorf("LoadConfig().Rollback = %v, want true", cfg.Rollback)
+	}
+	if cfg.HookTimeout != "5m" {
+		t.Errorf("LoadConfig().HookTimeout = %q, want %q", cfg.HookTimeout, "5m")
+	}
+	if cfg.HookFailure != "warn" {
+		t.Errorf("LoadConfig().HookFailure = %q, want %q", cfg.HookFailure, "warn")
+	}
+}
+
+func TestLoadConfig_CopyJobs(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	cfg, err := LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if cfg.CopyJobs != 0 {
+		t.Errorf("LoadConfig().CopyJobs default = %d, want 0", cfg.CopyJobs)
+	}
+
+	repo.Git("config", "wt.copyjobs", "16")
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if cfg.CopyJobs != 16 {
+		t.Errorf("LoadConfig().CopyJobs = %d, want 16", cfg.CopyJobs)
+	}
+
+	for _, invalid := range []string{"0", "-1", "many"} {
+		repo.Git("config", "wt.copyjobs", invalid)
+		if _, err := LoadConfig(t.Context()); err == nil {
+			t.Errorf("LoadConfig() should fail for wt.copyjobs %q", invalid)
+		}
+	}
+}
+
+func TestLoadConfig_ForBranch(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("config", "--add", "wt.hook", "echo global")
+	repo.Git("config", "--add", "wt.copy", "*.global")
+	repo.Git("config", "--add", "wt.frontend/*.hook", "npm ci")
+	repo.Git("config", "--add", "wt.frontend/*.hook", "npm run build")
+	repo.Git("config", "--add", "wt.frontend/*.symlink", "node_modules/")
+	repo.Git("config", "--add", "wt.backend/.copy", ".env")
+	repo.Git("config", "--add", "wt.release/1.2.deletehook", "echo bye")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	cfg, err := LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+
+	tests := []struct {
+		branch      string
+		hooks       []string
+		copy        []string
+		symlink     []string
+		deleteHooks []string
+	}{
+		{"", []string{"echo global"}, []string{"*.global"}, nil, nil},
+		{"main", []string{"echo global"}, []string{"*.global"}, nil, nil},
+		{"frontend/login", []string{"echo global", "npm ci", "npm run build"}, []string{"*.global"}, []string{"node_modules/"}, nil},
+		{"frontend/team/login", []string{"echo global"}, []string{"*.global"}, nil, nil},
+		{"backend/api/v2", []string{"echo global"}, []string{"*.global", ".env"}, nil, nil},
+		{"release/1.2", []string{"echo global"}, []string{"*.global"}, nil, []string{"echo bye"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			got := cfg.ForBranch(tt.branch)
+			if !slices.Equal(got.Hooks, tt.hooks) {
+				t.Errorf("Hooks = %q, want %q", got.Hooks, tt.hooks)
+			}
+			if !slices.Equal(got.Copy, tt.copy) {
+				t.Errorf("Copy = %q, want %q", got.Copy, tt.copy)
+			}
+			if !slices.Equal(got.Symlink, tt.symlink) {
+				t.Errorf("Symlink = %q, want %q", got.Symlink, tt.symlink)
+			}
+			if !slices.Equal(got.DeleteHooks, tt.deleteHooks) {
+				t.Errorf("DeleteHooks = %q, want %q", got.DeleteHooks, tt.deleteHooks)
+			}
+		})
+	}
+
+	// The global config is not modified
+	if !slices.Equal(cfg.Hooks, []string{"echo global"}) {
+		t.Errorf("global Hooks = %q, want unchanged", cfg.Hooks)
+	}
+}
+
+func TestMatchBranchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		branch  string
+		want    bool
+	}{
+		{"feature", "feature", true},
+		{"feature", "feature/x", false},
+		{"frontend/*", "frontend/login", true},
+		{"frontend/*", "frontend/team/login", false},
+		{"frontend/*", "frontend", false},
+		{"frontend/**", "frontend/team/login", true},
+		{"frontend/", "frontend/team/login", true},
+		{"**/hotfix-*", "release/1.2/hotfix-crash", true},
+		{"**/hotfix-*", "hotfix-crash", true},
+		{"feat-?", "feat-1", true},
+		{"[", "[", false},
+	}
+	for _, tt := range tests {
+		if got := MatchBranchPattern(tt.pattern, tt.branch); got != tt.want {
+			t.Errorf("MatchBranchPattern(%q, %q) = %v, want %v", tt.pattern, tt.branch, got, tt.want)
+		}
+	}
 }
 
 func TestExpandPath(t *testing.T) {
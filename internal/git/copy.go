@@ -4,9 +4,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 )
@@ -19,18 +24,74 @@ type CopyOptions struct {
 	NoCopy        []string
 	Copy          []string
 	Symlink       []string // Patterns for directories to symlink instead of copy (gitignore syntax)
+	Hardlink      []string // Patterns for files to hard link instead of copy (gitignore syntax)
 	ExcludeDirs   []string // Directories to exclude from copying (absolute paths)
+	Jobs          int      // Number of files copied in parallel; 0 means runtime.NumCPU()
+	Mode          string   // How files are copied (CopyModeAuto, CopyModeReflink or CopyModeCopy); empty means auto
+	Strict        bool     // Fail if any file cannot be copied, linked or symlinked
+	Source        string   // Worktree to copy from when adding a worktree; empty means the current worktree
+}
+
+// CopyPlan is the set of files and directories CopyFilesToWorktree would copy
+// or symlink from a source worktree, as computed by PlanCopy.
+type CopyPlan struct {
+	Source    string   // Source worktree root
+	Symlinks  []string // Top-level directories to symlink, relative to Source
+	Files     []string // Files to copy, relative to Source (excluding files inside Symlinks and Hardlinks)
+	Hardlinks []string // Files to hard link, relative to Source (excluding files inside Symlinks and tracked files)
+
+	// symlinkedFiles holds the files inside each directory of Symlinks. They
+	// are copied one by one if the directory cannot be symlinked.
+	symlinkedFiles map[string][]string
+	skipped        int         // Files excluded by NoCopy patterns
+	mode           string      // CopyOptions.Mode
+	jobs           int         // CopyOptions.Jobs
+	strict         bool        // CopyOptions.Strict
+	noClone        atomic.Bool // Set once the filesystem turned out not to support clones
+}
+
+// CopyResult summarizes what CopyPlan.Execute did.
+type CopyResult struct {
+	Copied     int           `json:"copied"`     // Files copied, including hard links that fell back to a copy
+	Bytes      int64         `json:"bytes"`      // Total size of the copied files
+	Hardlinked int           `json:"hardlinked"` // Files hard linked
+	Symlinks   int           `json:"symlinks"`   // Directories symlinked
+	Skipped    int           `json:"skipped"`    // Files excluded by nocopy patterns
+	Failures   []CopyFailure `json:"failures"`
+}
+
+// CopyFailure is a file that could not be copied or hard linked.
+type CopyFailure struct {
+	Path  string `json:"path"` // Relative to the source worktree root
+	Error string `json:"error"`
 }
 
 // CopyFilesToWorktree copies files to the new worktree based on options.
 // If w is non-nil, warnings about files that fail to copy are written to it.
-func CopyFilesToWorktree(ctx context.Context, srcRoot, dstRoot string, opts CopyOptions, warn io.Writer) error {
+func CopyFilesToWorktree(ctx context.Context, srcRoot, dstRoot string, opts CopyOptions, warn io.Writer) (*CopyResult, error) {
+	plan, err := PlanCopy(ctx, srcRoot, opts)
+	if err != nil {
+		return nil, err
+	}
+	return plan.Execute(ctx, dstRoot, warn)
+}
+
+// PlanCopy computes which files and directories would be copied or symlinked
+// from srcRoot based on options, without touching the destination.
+func PlanCopy(ctx context.Context, srcRoot string, opts CopyOptions) (*CopyPlan, error) {
+	if !ValidCopyMode(opts.Mode) {
+		return nil, fmt.Errorf("invalid copy mode %q: must be %s, %s or %s", opts.Mode, CopyModeAuto, CopyModeReflink, CopyModeCopy)
+	}
+	if opts.Jobs < 0 {
+		return nil, fmt.Errorf("invalid number of copy jobs %d: must be positive", opts.Jobs)
+	}
+
 	var files []string
 
 	if opts.CopyIgnored {
 		ignored, err := listIgnoredFiles(ctx, srcRoot)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		files = append(files, ignored...)
 	}
@@ -38,15 +99,21 @@ func CopyFilesToWorktree(ctx context.Context, srcRoot, dstRoot string, opts Copy
 	if opts.CopyUntracked {
 		untracked, err := ListUntrackedFiles(ctx, srcRoot)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		files = append(files, untracked...)
 	}
 
+	// Modified files are tracked, so git has already checked them out in the
+	// new worktree; they are copied over the checkout, never hard linked
+	tracked := make(map[string]struct{})
 	if opts.CopyModified {
 		modified, err := ListModifiedFiles(ctx, srcRoot)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		for _, file := range modified {
+			tracked[file] = struct{}{}
 		}
 		files = append(files, modified...)
 	}
@@ -55,7 +122,7 @@ func CopyFilesToWorktree(ctx context.Context, srcRoot, dstRoot string, opts Copy
 	if len(opts.Copy) > 0 {
 		copyFiles, err := listFilesMatchingCopyPatterns(ctx, srcRoot, opts.Copy)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		files = append(files, copyFiles...)
 	}
@@ -80,8 +147,25 @@ func CopyFilesToWorktree(ctx context.Context, srcRoot, dstRoot string, opts Copy
 		symlinkMatcher = gitignore.NewMatcher(patterns)
 	}
 
-	// Create symlinks for matching top-level directories before file-by-file copy
-	symlinkedDirs := make(map[string]struct{})
+	// Build Hardlink matcher using gitignore patterns
+	var hardlinkMatcher gitignore.Matcher
+	if len(opts.Hardlink) > 0 {
+		var patterns []gitignore.Pattern
+		for _, p := range opts.Hardlink {
+			patterns = append(patterns, gitignore.ParsePattern(p, nil))
+		}
+		hardlinkMatcher = gitignore.NewMatcher(patterns)
+	}
+
+	plan := &CopyPlan{
+		Source:         srcRoot,
+		symlinkedFiles: make(map[string][]string),
+		mode:           opts.Mode,
+		jobs:           opts.Jobs,
+		strict:         opts.Strict,
+	}
+
+	// Symlink matching top-level directories instead of copying file by file
 	if symlinkMatcher != nil {
 		dirs := collectTopLevelDirs(files)
 		for _, dir := range dirs {
@@ -95,21 +179,8 @@ func CopyFilesToWorktree(ctx context.Context, srcRoot, dstRoot string, opts Copy
 			if err != nil || !info.IsDir() {
 				continue
 			}
-
-			dstDir := filepath.Join(dstRoot, dir)
-			if err := os.MkdirAll(filepath.Dir(dstDir), 0755); err != nil {
-				if warn != nil {
-					fmt.Fprintf(warn, "warning: failed to create parent for symlink %s: %v\n", dir, err)
-				}
-				continue
-			}
-			if err := os.Symlink(srcDir, dstDir); err != nil {
-				if warn != nil {
-					fmt.Fprintf(warn, "warning: failed to symlink %s: %v\n", dir, err)
-				}
-				continue
-			}
-			symlinkedDirs[dir] = struct{}{}
+			plan.Symlinks = append(plan.Symlinks, dir)
+			plan.symlinkedFiles[dir] = nil
 		}
 	}
 
@@ -121,14 +192,6 @@ func CopyFilesToWorktree(ctx context.Context, srcRoot, dstRoot string, opts Copy
 		}
 		seen[file] = struct{}{}
 
-		// Skip files inside symlinked directories
-		topDir := topLevelDir(file)
-		if topDir != "" {
-			if _, ok := symlinkedDirs[topDir]; ok {
-				continue
-			}
-		}
-
 		// Skip files inside ExcludeDirs
 		src := filepath.Join(srcRoot, file)
 		shouldSkip := false
@@ -147,21 +210,176 @@ func CopyFilesToWorktree(ctx context.Context, srcRoot, dstRoot string, opts Copy
 		if noCopyMatcher != nil {
 			pathComponents := strings.Split(file, string(filepath.Separator))
 			if noCopyMatcher.Match(pathComponents, false) {
+				plan.skipped++
 				continue
 			}
 		}
 
-		dst := filepath.Join(dstRoot, file)
+		// Files inside symlinked directories are only copied as a fallback
+		topDir := topLevelDir(file)
+		if topDir != "" {
+			if fallback, ok := plan.symlinkedFiles[topDir]; ok {
+				plan.symlinkedFiles[topDir] = append(fallback, file)
+				continue
+			}
+		}
 
-		if err := copyFile(src, dst); err != nil {
+		_, isTracked := tracked[file]
+		if hardlinkMatcher != nil && !isTracked && hardlinkMatcher.Match(strings.Split(file, string(filepath.Separator)), false) {
+			plan.Hardlinks = append(plan.Hardlinks, file)
+			continue
+		}
+
+		plan.Files = append(plan.Files, file)
+	}
+
+	return plan, nil
+}
+
+// Execute copies, symlinks and hard links the planned files into dstRoot.
+// Failures are not fatal: if warn is non-nil, a warning is written to it for
+// each file or directory that could not be copied, symlinked or linked. Files
+// that cannot be hard linked because dstRoot is on another filesystem are
+// copied instead. It stops and returns the context error when ctx is
+// cancelled (e.g., on Ctrl-C). With CopyOptions.Strict, it returns an error
+// after copying if any file failed.
+//
+// Files are copied by a pool of CopyOptions.Jobs workers. Warnings are
+// written once all files are done, in plan order, so that the output does not
+// depend on scheduling.
+func (p *CopyPlan) Execute(ctx context.Context, dstRoot string, warn io.Writer) (*CopyResult, error) {
+	result := &CopyResult{Skipped: p.skipped, Failures: []CopyFailure{}}
+	ops := make([]copyOp, 0, len(p.Hardlinks)+len(p.Files))
+	for _, file := range p.Hardlinks {
+		ops = append(ops, copyOp{file: file, link: true})
+	}
+	for _, file := range p.Files {
+		ops = append(ops, copyOp{file: file})
+	}
+
+	for _, dir := range p.Symlinks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		srcDir := filepath.Join(p.Source, dir)
+		dstDir := filepath.Join(dstRoot, dir)
+		if err := os.MkdirAll(filepath.Dir(dstDir), 0755); err != nil {
+			if warn != nil {
+				fmt.Fprintf(warn, "warning: failed to create parent for symlink %s: %v\n", dir, err)
+			}
+			for _, file := range p.symlinkedFiles[dir] {
+				ops = append(ops, copyOp{file: file})
+			}
+			continue
+		}
+		if err := os.Symlink(srcDir, dstDir); err != nil {
 			if warn != nil {
-				fmt.Fprintf(warn, "warning: failed to copy %s: %v\n", file, err)
+				fmt.Fprintf(warn, "warning: failed to symlink %s: %v\n", dir, err)
+			}
+			for _, file := range p.symlinkedFiles[dir] {
+				ops = append(ops, copyOp{file: file})
 			}
 			continue
 		}
+		result.Symlinks++
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	// Create every destination directory once up front rather than once per
+	// file. A directory that cannot be created shows up as a failure of the
+	// files inside it.
+	dirs := make(map[string]struct{})
+	for _, op := range ops {
+		dirs[filepath.Dir(op.file)] = struct{}{}
+	}
+	for _, dir := range slices.Sorted(maps.Keys(dirs)) {
+		_ = os.MkdirAll(filepath.Join(dstRoot, dir), 0755) //nostyle:handlerrors
+	}
+
+	jobs := p.jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(jobs, len(ops)) {
+		wg.Go(func() {
+			for i := range next {
+				if ctx.Err() != nil {
+					continue
+				}
+				ops[i].run(p, dstRoot)
+			}
+		})
+	}
+	for i := range ops {
+		if ctx.Err() != nil {
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Report in plan order, so that the output does not depend on scheduling
+	warnedCrossDevice := false
+	for _, op := range ops {
+		if op.crossDevice && !warnedCrossDevice && warn != nil {
+			fmt.Fprintf(warn, "warning: cannot hard link files from %s into %s (different filesystems), copying them instead\n", p.Source, dstRoot)
+			warnedCrossDevice = true
+		}
+		switch {
+		case op.err == nil && op.link && !op.crossDevice:
+			result.Hardlinked++
+		case op.err == nil:
+			result.Copied++
+			result.Bytes += op.size
+		default:
+			result.Failures = append(result.Failures, CopyFailure{Path: op.file, Error: op.err.Error()})
+			if warn == nil {
+				continue
+			}
+			if op.link && !op.crossDevice {
+				fmt.Fprintf(warn, "warning: failed to hard link %s: %v\n", op.file, op.err)
+				continue
+			}
+			fmt.Fprintf(warn, "warning: failed to copy %s: %v\n", op.file, op.err)
+		}
+	}
+	if p.strict && len(result.Failures) > 0 {
+		return result, fmt.Errorf("%d file(s) could not be copied (strict mode)", len(result.Failures))
+	}
+	return result, nil
+}
+
+// copyOp is a file of a CopyPlan to copy or hard link, and its outcome.
+type copyOp struct {
+	file        string // Relative to the source and destination roots
+	link        bool   // Hard link instead of copy
+	crossDevice bool   // The hard link failed across filesystems and the file was copied instead
+	size        int64  // Size of the copied file
+	err         error
+}
+
+// run copies or links the file of op from the source of p to dstRoot, whose
+// parent directory must exist.
+func (op *copyOp) run(p *CopyPlan, dstRoot string) {
+	src := filepath.Join(p.Source, op.file)
+	dst := filepath.Join(dstRoot, op.file)
+	if op.link {
+		err := os.Link(src, dst)
+		if err == nil || !isCrossDevice(err) {
+			op.err = err
+			return
+		}
+		op.crossDevice = true
+	}
+	op.size, op.err = copyFile(src, dst, p.mode, &p.noClone)
 }
 
 // topLevelDir returns the first path component if the file is inside a directory,
@@ -285,4 +503,3 @@ func listFilesMatchingCopyPatterns(ctx context.Context, root string, patterns []
 
 	return result, nil
 }
-
@@ -1,34 +1,105 @@
-//go:build !darwin
-
-// Default implementation for non-Darwin platforms.
-// On Linux (kernel 4.5+), io.Copy internally attempts copy_file_range(2),
-// which enables efficient in-kernel copying on supported filesystems
-// (Btrfs, XFS, ext4, NFS 4.2+, etc.) without data passing through userspace.
+// Shared implementation of copyFile. Depending on the copy mode, a file is
+// first cloned with the platform's copy-on-write support (cloneFile), which
+// shares data blocks with the source until either file is modified, and is
+// otherwise copied byte by byte.
 
 package git
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync/atomic"
+)
+
+// Copy modes (wt.copymode).
+const (
+	// CopyModeAuto clones files when the filesystem supports it and copies
+	// them otherwise.
+	CopyModeAuto = "auto"
+	// CopyModeReflink only clones files; files that cannot be cloned are
+	// reported as failed.
+	CopyModeReflink = "reflink"
+	// CopyModeCopy always copies the data, so that worktrees never share
+	// blocks.
+	CopyModeCopy = "copy"
 )
 
-func copyFile(src, dst string) (err error) {
+// errCloneUnsupported is returned by cloneFile on platforms without
+// copy-on-write clones.
+var errCloneUnsupported = errors.New("copy-on-write clones are not supported on this platform")
+
+// ValidCopyMode reports whether mode is a valid wt.copymode. The empty string
+// means CopyModeAuto.
+func ValidCopyMode(mode string) bool {
+	return mode == "" || slices.Contains([]string{CopyModeAuto, CopyModeReflink, CopyModeCopy}, mode)
+}
+
+// copyFile copies src to dst, whose parent directory must exist, preserving
+// its permissions and modification time. It returns the size of the file.
+// noClone is shared by the files of a copy: it is set once the filesystem
+// turns out not to support clones, after which auto mode copies right away.
+func copyFile(src, dst, mode string, noClone *atomic.Bool) (int64, error) {
 	srcInfo, err := os.Stat(src)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Skip directories
 	if srcInfo.IsDir() {
-		return nil
+		return 0, nil
 	}
 
-	// Create parent directory
-	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+	if mode == CopyModeReflink || (mode != CopyModeCopy && !noClone.Load()) {
+		err := cloneFileTo(src, dst, srcInfo)
+		if err == nil {
+			return srcInfo.Size(), nil
+		}
+		if mode == CopyModeReflink {
+			return 0, fmt.Errorf("failed to clone (wt.copymode is reflink): %w", err)
+		}
+		if isCloneUnsupported(err) {
+			noClone.Store(true)
+		}
+	}
+
+	// Fall back to a traditional copy (unsupported filesystem, cross-device,
+	// etc.). In auto mode, io.Copy still lets the kernel copy the data
+	// without passing it through userspace where possible (copy_file_range(2)
+	// on Linux).
+	if err := copyFileTraditional(src, dst, srcInfo, mode != CopyModeCopy); err != nil {
+		return 0, err
+	}
+	return srcInfo.Size(), nil
+}
+
+// cloneFileTo clones src to dst. An existing dst (e.g., a file git just
+// checked out for wt.copymodified) is replaced by renaming a clone in a
+// temporary file over it, so that a failed clone leaves it untouched.
+func cloneFileTo(src, dst string, srcInfo os.FileInfo) error {
+	if _, err := os.Lstat(dst); errors.Is(err, os.ErrNotExist) {
+		return cloneFile(src, dst, srcInfo)
+	}
+	tmp := filepath.Join(filepath.Dir(dst), fmt.Sprintf(".%s.git-wt-%d", filepath.Base(dst), os.Getpid()))
+	if err := cloneFile(src, tmp, srcInfo); err != nil {
+		// The name is unique to this process, so whatever is there is ours
+		_ = os.Remove(tmp) //nostyle:handlerrors
 		return err
 	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp) //nostyle:handlerrors
+		return err
+	}
+	return nil
+}
 
+// copyFileTraditional copies the contents and metadata of src to dst. Without
+// offload, the data is read and written in userspace, as copy_file_range(2)
+// may share blocks between the files on filesystems such as Btrfs and XFS.
+func copyFileTraditional(src, dst string, srcInfo os.FileInfo, offload bool) (err error) {
 	in, err := os.Open(src)
 	if err != nil {
 		return err
@@ -45,7 +116,13 @@ func copyFile(src, dst string) (err error) {
 		}
 	}()
 
-	if _, err := io.Copy(out, in); err != nil {
+	if offload {
+		_, err = io.Copy(out, in)
+	} else {
+		// Hide ReadFrom and WriteTo so that io.Copy uses a plain buffer
+		_, err = io.Copy(struct{ io.Writer }{out}, struct{ io.Reader }{in})
+	}
+	if err != nil {
 		return err
 	}
 
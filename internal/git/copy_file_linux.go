@@ -0,0 +1,57 @@
+//go:build linux
+
+// Linux implementation using the FICLONE ioctl for Copy-on-Write.
+// On filesystems with reflink support (Btrfs, XFS, bcachefs, etc.), the clone
+// shares data blocks with the source until modified, making copies nearly
+// instantaneous regardless of file size.
+
+package git
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneFile clones src into dst, which must not exist. A dst it created is
+// removed again if the clone fails.
+func cloneFile(src, dst string, srcInfo os.FileInfo) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := out.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = os.Remove(dst) //nostyle:handlerrors
+		}
+	}()
+
+	if err := unix.IoctlFileClone(int(out.Fd()), int(in.Fd())); err != nil {
+		return err
+	}
+
+	// Preserve file permissions
+	if err := os.Chmod(dst, srcInfo.Mode()); err != nil {
+		return err
+	}
+
+	// Preserve file timestamps
+	return os.Chtimes(dst, srcInfo.ModTime(), srcInfo.ModTime())
+}
+
+// isCloneUnsupported reports whether err means that FICLONE cannot clone
+// between the filesystems of a copy, so that cloning the other files of the
+// copy would fail as well.
+func isCloneUnsupported(err error) bool {
+	return errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.EXDEV) || errors.Is(err, unix.EINVAL)
+}
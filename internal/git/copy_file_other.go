@@ -0,0 +1,19 @@
+//go:build !darwin && !linux
+
+// Default implementation for platforms without copy-on-write clones.
+// Files are always copied.
+
+package git
+
+import (
+	"errors"
+	"os"
+)
+
+func cloneFile(_, _ string, _ os.FileInfo) error {
+	return errCloneUnsupported
+}
+
+func isCloneUnsupported(err error) bool {
+	return errors.Is(err, errCloneUnsupported)
+}
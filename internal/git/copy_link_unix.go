@@ -0,0 +1,13 @@
+//go:build !windows
+
+package git
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isCrossDevice reports whether err is a failure to link across filesystems.
+func isCrossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
@@ -1,8 +1,16 @@
 package git
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -28,7 +36,7 @@ func TestCopyFilesToWorktree_Ignored(t *testing.T) {
 	defer restore()
 
 	opts := CopyOptions{CopyIgnored: true}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -68,7 +76,7 @@ func TestCopyFilesToWorktree_Untracked(t *testing.T) {
 	defer restore()
 
 	opts := CopyOptions{CopyUntracked: true}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -107,7 +115,7 @@ func TestCopyFilesToWorktree_Modified(t *testing.T) {
 	defer restore()
 
 	opts := CopyOptions{CopyModified: true}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -149,7 +157,7 @@ func TestCopyFilesToWorktree_NoOptions(t *testing.T) {
 
 	// No copy options enabled
 	opts := CopyOptions{}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -181,7 +189,7 @@ func TestCopyFilesToWorktree_Subdirectory(t *testing.T) {
 	defer restore()
 
 	opts := CopyOptions{CopyIgnored: true}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -227,7 +235,7 @@ func TestCopyFilesToWorktree_NoCopy(t *testing.T) {
 		CopyIgnored: true,
 		NoCopy:      []string{"*.log", "vendor/"},
 	}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -280,7 +288,7 @@ func TestCopyFilesToWorktree_NoCopy_GitignorePatterns(t *testing.T) {
 		CopyIgnored: true,
 		NoCopy:      []string{"build/"},
 	}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -329,7 +337,7 @@ func TestCopyFilesToWorktree_Copy(t *testing.T) {
 		CopyIgnored: false,
 		Copy:        []string{"*.code-workspace"},
 	}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -376,7 +384,7 @@ func TestCopyFilesToWorktree_Copy_WithNoCopy(t *testing.T) {
 		Copy:        []string{"*.code-workspace"},
 		NoCopy:      []string{"other.code-workspace"},
 	}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -422,7 +430,7 @@ func TestCopyFilesToWorktree_Copy_MultiplePatterns(t *testing.T) {
 		CopyIgnored: false,
 		Copy:        []string{"*.code-workspace", ".vscode/"},
 	}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -471,7 +479,7 @@ func TestCopyFilesToWorktree_Copy_WithCopyIgnored(t *testing.T) {
 		CopyIgnored: true,
 		Copy:        []string{"*.code-workspace"},
 	}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -511,7 +519,7 @@ func TestCopyFilesToWorktree_Copy_MatchesUntrackedFiles(t *testing.T) {
 		CopyIgnored: false,
 		Copy:        []string{"untracked.txt"},
 	}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -542,7 +550,7 @@ func TestCopyFile_PreservesTimestamps(t *testing.T) {
 		t.Fatalf("failed to set source file time: %v", err)
 	}
 
-	if err := copyFile(srcPath, dstPath); err != nil {
+	if _, err := copyFile(srcPath, dstPath, CopyModeAuto, new(atomic.Bool)); err != nil {
 		t.Fatalf("copyFile failed: %v", err)
 	}
 
@@ -556,6 +564,103 @@ func TestCopyFile_PreservesTimestamps(t *testing.T) {
 	}
 }
 
+func TestCopyFile_Modes(t *testing.T) {
+	tmpDir := t.TempDir()
+	srcPath := filepath.Join(tmpDir, "src.txt")
+	if err := os.WriteFile(srcPath, []byte("content"), 0640); err != nil {
+		t.Fatalf("failed to create source file: %v", err)
+	}
+
+	for _, mode := range []string{CopyModeAuto, CopyModeCopy, CopyModeReflink} {
+		t.Run(mode, func(t *testing.T) {
+			dstPath := filepath.Join(tmpDir, mode+".txt")
+			_, err := copyFile(srcPath, dstPath, mode, new(atomic.Bool))
+			if err != nil {
+				// Only reflink may fail, on filesystems without clones
+				if mode != CopyModeReflink {
+					t.Fatalf("copyFile failed: %v", err)
+				}
+				if _, serr := os.Stat(dstPath); !os.IsNotExist(serr) {
+					t.Errorf("a failed clone should not leave %s behind", dstPath)
+				}
+				return
+			}
+			got, err := os.ReadFile(dstPath)
+			if err != nil {
+				t.Fatalf("failed to read destination file: %v", err)
+			}
+			if string(got) != "content" {
+				t.Errorf("destination content = %q, want %q", got, "content")
+			}
+			info, err := os.Stat(dstPath)
+			if err != nil {
+				t.Fatalf("failed to stat destination file: %v", err)
+			}
+			if runtime.GOOS != "windows" && info.Mode().Perm() != 0640 {
+				t.Errorf("destination mode = %v, want 0640", info.Mode().Perm())
+			}
+		})
+	}
+
+	if _, err := PlanCopy(t.Context(), tmpDir, CopyOptions{Mode: "fast"}); err == nil {
+		t.Error("PlanCopy should fail for an unknown copy mode")
+	}
+
+	// A file that already exists (e.g., checked out by git for
+	// wt.copymodified) is replaced by a clone or left untouched
+	t.Run("reflink_existing", func(t *testing.T) {
+		dstPath := filepath.Join(tmpDir, "existing.txt")
+		if err := os.WriteFile(dstPath, []byte("checked out"), 0600); err != nil {
+			t.Fatalf("failed to create destination file: %v", err)
+		}
+		want := "content"
+		if _, err := copyFile(srcPath, dstPath, CopyModeReflink, new(atomic.Bool)); err != nil {
+			want = "checked out"
+		}
+		got, err := os.ReadFile(dstPath)
+		if err != nil {
+			t.Fatalf("destination file should exist: %v", err)
+		}
+		if string(got) != want {
+			t.Errorf("destination content = %q, want %q", got, want)
+		}
+		entries, err := os.ReadDir(tmpDir)
+		if err != nil {
+			t.Fatalf("failed to read dir: %v", err)
+		}
+		for _, e := range entries {
+			if strings.Contains(e.Name(), ".git-wt-") {
+				t.Errorf("temporary file %s should not be left behind", e.Name())
+			}
+		}
+	})
+
+	// Auto mode stops trying to clone once the filesystem turned out not to
+	// support it, and still copies every file
+	t.Run("auto_unsupported", func(t *testing.T) {
+		_, rerr := copyFile(srcPath, filepath.Join(tmpDir, "probe.txt"), CopyModeReflink, new(atomic.Bool))
+		unsupported := rerr != nil && isCloneUnsupported(rerr)
+
+		var noClone atomic.Bool
+		for _, name := range []string{"auto1.txt", "auto2.txt"} {
+			dstPath := filepath.Join(tmpDir, name)
+			if _, err := copyFile(srcPath, dstPath, CopyModeAuto, &noClone); err != nil {
+				t.Fatalf("copyFile failed: %v", err)
+			}
+			got, err := os.ReadFile(dstPath)
+			if err != nil {
+				t.Fatalf("failed to read destination file: %v", err)
+			}
+			if string(got) != "content" {
+				t.Errorf("destination content = %q, want %q", got, "content")
+			}
+		}
+		if noClone.Load() != unsupported {
+			t.Errorf("noClone = %v, want %v (reflink error: %v)", noClone.Load(), unsupported, rerr)
+		}
+	})
+}
+
 func TestCopyFilesToWorktree_Symlink(t *testing.T) {
 	repo := testutil.NewTestRepo(t)
 	repo.CreateFile("README.md", "# Test")
@@ -578,7 +683,7 @@ func TestCopyFilesToWorktree_Symlink(t *testing.T) {
 		CopyIgnored: true,
 		Symlink:     []string{"node_modules/"},
 	}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -645,7 +750,7 @@ func TestCopyFilesToWorktree_Symlink_NonMatchingDir(t *testing.T) {
 		CopyIgnored: true,
 		Symlink:     []string{"node_modules/"},
 	}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -672,6 +777,112 @@ func TestCopyFilesToWorktree_Symlink_NonMatchingDir(t *testing.T) {
 	}
 }
 
+func TestCopyFilesToWorktree_Hardlink(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "models/\n.env\n")
+	repo.Commit("initial commit")
+
+	repo.CreateFile("models/weights.bin", "weights")
+	repo.CreateFile(".env", "SECRET=value")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{
+		CopyIgnored: true,
+		Hardlink:    []string{"models/"},
+	}
+	plan, err := PlanCopy(t.Context(), repo.Root, opts)
+	if err != nil {
+		t.Fatalf("PlanCopy failed: %v", err)
+	}
+	if !slices.Equal(plan.Hardlinks, []string{filepath.Join("models", "weights.bin")}) {
+		t.Errorf("Hardlinks = %q, want [models/weights.bin]", plan.Hardlinks)
+	}
+	if !slices.Equal(plan.Files, []string{".env"}) {
+		t.Errorf("Files = %q, want [.env]", plan.Files)
+	}
+	if _, err := plan.Execute(t.Context(), dstDir, nil); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	srcInfo, err := os.Stat(filepath.Join(repo.Root, "models", "weights.bin"))
+	if err != nil {
+		t.Fatalf("failed to stat source file: %v", err)
+	}
+	dstInfo, err := os.Stat(filepath.Join(dstDir, "models", "weights.bin"))
+	if err != nil {
+		t.Fatalf("hard linked file should exist: %v", err)
+	}
+	if !os.SameFile(srcInfo, dstInfo) {
+		t.Error("models/weights.bin should be hard linked to the source")
+	}
+
+	srcInfo, err = os.Stat(filepath.Join(repo.Root, ".env"))
+	if err != nil {
+		t.Fatalf("failed to stat source file: %v", err)
+	}
+	dstInfo, err = os.Stat(filepath.Join(dstDir, ".env"))
+	if err != nil {
+		t.Fatalf(".env should be copied: %v", err)
+	}
+	if os.SameFile(srcInfo, dstInfo) {
+		t.Error(".env should be copied, not hard linked")
+	}
+}
+
+func TestAddWorktree_HardlinkModified(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile("data/config.json", "{}")
+	repo.Commit("initial commit")
+
+	// A tracked file that matches a hard link pattern and is modified
+	repo.CreateFile("data/config.json", `{"local": true}`)
+
+	restore := repo.Chdir()
+	defer restore()
+
+	wtPath := filepath.Join(repo.ParentDir(), "wt-modified")
+	opts := CopyOptions{
+		CopyModified: true,
+		Hardlink:     []string{"data/"},
+		Strict:       true,
+	}
+	result, err := AddWorktreeWithNewBranch(t.Context(), wtPath, "modified", "", opts)
+	if err != nil {
+		t.Fatalf("AddWorktreeWithNewBranch failed: %v", err)
+	}
+	if result.Copied != 1 || result.Hardlinked != 0 || len(result.Failures) != 0 {
+		t.Errorf("result = %+v, want the modified file copied", result)
+	}
+
+	content, err := os.ReadFile(filepath.Join(wtPath, "data", "config.json"))
+	if err != nil {
+		t.Fatalf("failed to read copied file: %v", err)
+	}
+	if string(content) != `{"local": true}` {
+		t.Errorf("data/config.json = %q, want the modified content", content)
+	}
+	srcInfo, err := os.Stat(repo.Path("data/config.json"))
+	if err != nil {
+		t.Fatalf("failed to stat source file: %v", err)
+	}
+	dstInfo, err := os.Stat(filepath.Join(wtPath, "data", "config.json"))
+	if err != nil {
+		t.Fatalf("failed to stat copied file: %v", err)
+	}
+	if os.SameFile(srcInfo, dstInfo) {
+		t.Error("a tracked file should be copied, not hard linked")
+	}
+}
+
 func TestCopyFilesToWorktree_ExcludeDirs(t *testing.T) {
 	repo := testutil.NewTestRepo(t)
 	repo.CreateFile("README.md", "# Test")
@@ -698,7 +909,7 @@ func TestCopyFilesToWorktree_ExcludeDirs(t *testing.T) {
 		CopyIgnored: true,
 		ExcludeDirs: []string{filepath.Join(repo.Root, ".worktrees")},
 	}
-	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
+	_, err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts, nil)
 	if err != nil {
 		t.Fatalf("CopyFilesToWorktree failed: %v", err)
 	}
@@ -719,3 +930,207 @@ func TestCopyFilesToWorktree_ExcludeDirs(t *testing.T) {
 		t.Error(".worktrees/.gitignore should NOT have been copied")
 	}
 }
+
+func TestPlanCopy(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "node_modules/\n.env\n*.log\n")
+	repo.Commit("initial commit")
+
+	repo.CreateFile("node_modules/pkg-a/index.js", "module.exports = 'a'")
+	repo.CreateFile(".env", "SECRET=value")
+	repo.CreateFile("app.log", "log content")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{
+		CopyIgnored: true,
+		NoCopy:      []string{"*.log"},
+		Symlink:     []string{"node_modules/"},
+	}
+	plan, err := PlanCopy(t.Context(), repo.Root, opts)
+	if err != nil {
+		t.Fatalf("PlanCopy failed: %v", err)
+	}
+
+	if plan.Source != repo.Root {
+		t.Errorf("Source = %q, want %q", plan.Source, repo.Root)
+	}
+	if !slices.Equal(plan.Symlinks, []string{"node_modules"}) {
+		t.Errorf("Symlinks = %v, want [node_modules]", plan.Symlinks)
+	}
+	if !slices.Equal(plan.Files, []string{".env"}) {
+		t.Errorf("Files = %v, want [.env]", plan.Files)
+	}
+
+	// Executing applies the plan
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+	if _, err := plan.Execute(t.Context(), dstDir, nil); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if fi, err := os.Lstat(filepath.Join(dstDir, "node_modules")); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("node_modules should be a symlink: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, ".env")); err != nil {
+		t.Errorf(".env should be copied: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "app.log")); !os.IsNotExist(err) {
+		t.Error("app.log should not be copied")
+	}
+}
+
+func TestCopyPlan_Execute_Cancelled(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", ".env\n")
+	repo.Commit("initial commit")
+	repo.CreateFile(".env", "SECRET=value")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	plan, err := PlanCopy(t.Context(), repo.Root, CopyOptions{CopyIgnored: true})
+	if err != nil {
+		t.Fatalf("PlanCopy failed: %v", err)
+	}
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := plan.Execute(ctx, dstDir, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Execute error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, ".env")); !os.IsNotExist(err) {
+		t.Error(".env should not be copied after cancellation")
+	}
+}
+
+func TestCopyPlan_Execute_Parallel(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "cache/\nbroken/\n")
+	repo.Commit("initial commit")
+	for i := range 50 {
+		repo.CreateFile(fmt.Sprintf("cache/%02d/file.txt", i), fmt.Sprintf("content %d", i))
+		repo.CreateFile(fmt.Sprintf("broken/%02d.txt", i), "content")
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	plan, err := PlanCopy(t.Context(), repo.Root, CopyOptions{CopyIgnored: true, Jobs: 8})
+	if err != nil {
+		t.Fatalf("PlanCopy failed: %v", err)
+	}
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+	// A file where a directory is expected makes every file below it fail
+	if err := os.WriteFile(filepath.Join(dstDir, "broken"), nil, 0600); err != nil {
+		t.Fatalf("failed to create blocking file: %v", err)
+	}
+
+	var warn bytes.Buffer
+	if _, err := plan.Execute(t.Context(), dstDir, &warn); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	for i := range 50 {
+		got, err := os.ReadFile(filepath.Join(dstDir, "cache", fmt.Sprintf("%02d", i), "file.txt"))
+		if err != nil {
+			t.Fatalf("file %d should be copied: %v", i, err)
+		}
+		if want := fmt.Sprintf("content %d", i); string(got) != want {
+			t.Errorf("file %d content = %q, want %q", i, got, want)
+		}
+	}
+
+	// Warnings follow the plan order regardless of which worker failed first
+	var failed []string
+	for line := range strings.Lines(warn.String()) {
+		file, _, ok := strings.Cut(strings.TrimPrefix(line, "warning: failed to copy "), ":")
+		if !ok {
+			t.Fatalf("unexpected warning: %q", line)
+		}
+		failed = append(failed, file)
+	}
+	var want []string
+	for _, file := range plan.Files {
+		if strings.HasPrefix(file, "broken"+string(filepath.Separator)) {
+			want = append(want, file)
+		}
+	}
+	if len(want) != 50 || !slices.Equal(failed, want) {
+		t.Errorf("warnings for %q, want %q in plan order", failed, want)
+	}
+}
+
+func TestCopyPlan_Execute_Result(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "node_modules/\nmodels/\n*.log\n.env\nbroken\n")
+	repo.Commit("initial commit")
+
+	repo.CreateFile("node_modules/pkg/index.js", "module.exports = 1")
+	repo.CreateFile("models/weights.bin", "weights")
+	repo.CreateFile("debug.log", "log")
+	repo.CreateFile(".env", "SECRET=value")
+	if err := os.Symlink(filepath.Join(repo.Root, "missing"), filepath.Join(repo.Root, "broken")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{
+		CopyIgnored: true,
+		NoCopy:      []string{"*.log"},
+		Symlink:     []string{"node_modules/"},
+		Hardlink:    []string{"models/"},
+	}
+	plan, err := PlanCopy(t.Context(), repo.Root, opts)
+	if err != nil {
+		t.Fatalf("PlanCopy failed: %v", err)
+	}
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	result, err := plan.Execute(t.Context(), dstDir, nil)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	want := CopyResult{
+		Copied:     1,
+		Bytes:      int64(len("SECRET=value")),
+		Hardlinked: 1,
+		Symlinks:   1,
+		Skipped:    1,
+	}
+	if result.Copied != want.Copied || result.Bytes != want.Bytes || result.Hardlinked != want.Hardlinked || result.Symlinks != want.Symlinks || result.Skipped != want.Skipped {
+		t.Errorf("Execute result = %+v, want %+v", *result, want)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].Path != "broken" {
+		t.Errorf("Failures = %+v, want the dangling symlink", result.Failures)
+	}
+
+	// In strict mode, the same failure is an error
+	opts.Strict = true
+	plan, err = PlanCopy(t.Context(), repo.Root, opts)
+	if err != nil {
+		t.Fatalf("PlanCopy failed: %v", err)
+	}
+	result, err = plan.Execute(t.Context(), filepath.Join(repo.ParentDir(), "dst-strict"), nil)
+	if err == nil {
+		t.Fatal("Execute should fail in strict mode")
+	}
+	if result == nil || len(result.Failures) != 1 {
+		t.Errorf("Execute should return the result with the failure, got %+v", result)
+	}
+}
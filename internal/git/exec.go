@@ -6,6 +6,14 @@ import (
 	"github.com/k1LoW/exec"
 )
 
+// internalIdentityEnv is the identity of the commits git-wt creates for its
+// own bookkeeping, so that creating them does not depend on user.name and
+// user.email.
+var internalIdentityEnv = []string{
+	"GIT_AUTHOR_NAME=git-wt", "GIT_AUTHOR_EMAIL=git-wt@localhost",
+	"GIT_COMMITTER_NAME=git-wt", "GIT_COMMITTER_EMAIL=git-wt@localhost",
+}
+
 // gitCommand creates an exec.Cmd for git with the given context and arguments.
 // It uses exec.LookPath to look up the git binary path.
 func gitCommand(ctx context.Context, args ...string) (*exec.Cmd, error) {
@@ -1,24 +1,263 @@
 package git
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/k1LoW/exec"
 )
 
-// RunHooks executes the configured hooks in the given directory.
+// Hook actions exported as GIT_WT_ACTION.
+const (
+	HookActionCreate = "create"
+	HookActionSwitch = "switch"
+	HookActionDelete = "delete"
+	HookActionMove   = "move"
+)
+
+// HookEnv describes the operation a hook, delete hook or remover runs for.
+// It is exported to the command as GIT_WT_* environment variables, so that
+// hooks do not have to re-derive it.
+type HookEnv struct {
+	Action     string `json:"action"`                // GIT_WT_ACTION: one of the HookAction* constants
+	Branch     string `json:"branch,omitempty"`      // GIT_WT_BRANCH: branch of the worktree (empty for a detached HEAD)
+	Path       string `json:"path"`                  // GIT_WT_PATH: worktree path
+	SourcePath string `json:"source_path,omitempty"` // GIT_WT_SOURCE_PATH: worktree git-wt was run from (empty at a bare root)
+	MainRoot   string `json:"main_root"`             // GIT_WT_MAIN_ROOT: main repository root
+	StartPoint string `json:"start_point,omitempty"` // GIT_WT_START_POINT: start-point of a new branch
+	OldPath    string `json:"old_path,omitempty"`    // GIT_WT_OLD_PATH: previous worktree path of a moved worktree
+}
+
+// Environ returns the environment of the current process with the GIT_WT_*
+// variables of e added. Empty values are exported too, so that values
+// inherited from an outer git-wt invocation do not leak into the hook.
+func (e HookEnv) Environ() []string {
+	return append(os.Environ(),
+		"GIT_WT_ACTION="+e.Action,
+		"GIT_WT_BRANCH="+e.Branch,
+		"GIT_WT_PATH="+e.Path,
+		"GIT_WT_SOURCE_PATH="+e.SourcePath,
+		"GIT_WT_MAIN_ROOT="+e.MainRoot,
+		"GIT_WT_START_POINT="+e.StartPoint,
+		"GIT_WT_OLD_PATH="+e.OldPath,
+	)
+}
+
+// Hook failure policies (wt.hookfailure and the inline failure option).
+const (
+	HookFailureAbort    = "abort"    // Stop and fail the operation
+	HookFailureWarn     = "warn"     // Print a warning and continue
+	HookFailureRollback = "rollback" // Fail and undo the operation where possible
+)
+
+// HookOptions are the defaults for running hooks. A hook can override them
+// with an inline option prefix, e.g. "[timeout=30s,failure=warn] npm ci".
+// The "async" option runs a hook in the background (see RunHooks).
+type HookOptions struct {
+	Timeout time.Duration `json:"timeout"` // Kill a hook that runs longer than this (0: no timeout)
+	Failure string        `json:"failure"` // One of the HookFailure* policies
+}
+
+// HookOptions returns the hook options of c (wt.hooktimeout and
+// wt.hookfailure).
+func (c Config) HookOptions() (HookOptions, error) {
+	opts := HookOptions{Failure: HookFailureAbort}
+	if c.HookTimeout != "" {
+		d, err := parseHookTimeout(c.HookTimeout)
+		if err != nil {
+			return opts, fmt.Errorf("invalid wt.hooktimeout: %w", err)
+		}
+		opts.Timeout = d
+	}
+	if c.HookFailure != "" {
+		if !validHookFailure(c.HookFailure) {
+			return opts, fmt.Errorf("invalid wt.hookfailure %q: must be one of abort, warn or rollback", c.HookFailure)
+		}
+		opts.Failure = c.HookFailure
+	}
+	return opts, nil
+}
+
+// HookError is returned when a hook fails or times out.
+type HookError struct {
+	Hook    string // The hook as configured, including its option prefix
+	Failure string // Failure policy that applies to the hook
+	Err     error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("hook %q failed: %v", e.Hook, e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// hookOptionPrefix matches an inline option prefix such as "[timeout=30s] ".
+// Shell tests like "[ -f go.mod ] && ..." do not match because options
+// contain no spaces.
+var hookOptionPrefix = regexp.MustCompile(`(?s)^\[([a-z]+(?:=[^\s,\]]+)?(?:,[a-z]+(?:=[^\s,\]]+)?)*)\]\s+(.*)$`)
+
+// hookSpec is a hook command with its options resolved.
+type hookSpec struct {
+	command string
+	timeout time.Duration
+	failure string
+	async   bool
+}
+
+// parseHook splits the inline option prefix off hook and applies it on top
+// of the defaults in opts.
+func parseHook(hook string, opts HookOptions) (hookSpec, error) {
+	spec := hookSpec{command: hook, timeout: opts.Timeout, failure: opts.Failure}
+	if spec.failure == "" {
+		spec.failure = HookFailureAbort
+	}
+	m := hookOptionPrefix.FindStringSubmatch(hook)
+	if m == nil {
+		return spec, nil
+	}
+	spec.command = m[2]
+	for _, opt := range strings.Split(m[1], ",") {
+		key, val, _ := strings.Cut(opt, "=")
+		switch key {
+		case "timeout":
+			d, err := parseHookTimeout(val)
+			if err != nil {
+				return spec, err
+			}
+			spec.timeout = d
+		case "async":
+			if val != "" {
+				return spec, fmt.Errorf("hook option async does not take a value")
+			}
+			spec.async = true
+		case "failure":
+			if !validHookFailure(val) {
+				return spec, fmt.Errorf("invalid failure policy %q: must be one of abort, warn or rollback", val)
+			}
+			spec.failure = val
+		default:
+			return spec, fmt.Errorf("unknown hook option %q", key)
+		}
+	}
+	return spec, nil
+}
+
+// parseHookTimeout parses a timeout such as "30s" or "5m". "0" disables the
+// timeout.
+func parseHookTimeout(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", s, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must not be negative", s)
+	}
+	return d, nil
+}
+
+func validHookFailure(s string) bool {
+	switch s {
+	case HookFailureAbort, HookFailureWarn, HookFailureRollback:
+		return true
+	}
+	return false
+}
+
+var errHookTimedOut = errors.New("timed out")
+
+// runHook runs a single hook with sh in dir. When the hook times out, its
+// whole process group is killed so that commands it started do not keep
+// running (or keep git-wt waiting).
+func runHook(ctx context.Context, spec hookSpec, dir string, env HookEnv, stdout, stderr io.Writer) error {
+	hookCtx := ctx
+	if spec.timeout > 0 {
+		var cancel context.CancelFunc
+		hookCtx, cancel = context.WithTimeout(ctx, spec.timeout)
+		defer cancel()
+	}
+	// k1LoW/exec starts the command in its own process group and kills the
+	// group when hookCtx is done
+	cmd := exec.CommandContext(hookCtx, "sh", "-c", spec.command)
+	cmd.Dir = dir
+	cmd.Env = env.Environ()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	if err != nil && ctx.Err() == nil && errors.Is(hookCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s", errHookTimedOut, spec.timeout)
+	}
+	return err
+}
+
+// RunHooks executes the configured hooks in the given directory with the
+// GIT_WT_* variables of env.
 // Hook stdout/stderr are written to the provided writer.
-// If a hook fails, it stops immediately and returns the error.
-func RunHooks(ctx context.Context, hooks []string, dir string, w io.Writer) error {
+// If a hook fails or times out, it stops immediately and returns a
+// *HookError, unless the hook's failure policy is "warn", in which case a
+// warning is written to w and the remaining hooks still run.
+// Hooks with the async option are started in the background once the other
+// hooks have succeeded, with their output going to a log file (see
+// HookJob). Delete hooks always run synchronously, as the worktree is removed
+// right after them.
+func RunHooks(ctx context.Context, hooks []string, dir string, env HookEnv, opts HookOptions, w io.Writer) error {
+	var async []string
 	for _, hook := range hooks {
-		cmd := exec.CommandContext(ctx, "sh", "-c", hook)
-		cmd.Dir = dir
-		cmd.Stdout = w
-		cmd.Stderr = w
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("hook %q failed: %w", hook, err)
+		spec, err := parseHook(hook, opts)
+		if err != nil {
+			return &HookError{Hook: hook, Failure: HookFailureAbort, Err: err}
+		}
+		if spec.async && env.Action != HookActionDelete {
+			async = append(async, hook)
+			continue
+		}
+		if err := runHook(ctx, spec, dir, env, w, w); err != nil {
+			herr := &HookError{Hook: hook, Failure: spec.failure, Err: err}
+			if spec.failure == HookFailureWarn && ctx.Err() == nil {
+				fmt.Fprintf(w, "warning: %v\n", herr)
+				continue
+			}
+			return herr
+		}
+	}
+	if len(async) > 0 {
+		job, err := startHookJob(ctx, async, dir, env, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Running %d hook(s) in the background (log: %s)\n", len(async), job.Log)
+	}
+	return nil
+}
+
+// RunPreHooks executes hooks that can veto an operation, such as pre-create
+// hooks, in the given directory with the GIT_WT_* variables of env. Hook
+// stdout is written to the provided writer. If a hook fails or times out,
+// it stops immediately and returns an error carrying the hook's stderr.
+// Failure policies do not apply: a failing pre-hook always vetoes.
+func RunPreHooks(ctx context.Context, hooks []string, dir string, env HookEnv, opts HookOptions, w io.Writer) error {
+	for _, hook := range hooks {
+		spec, err := parseHook(hook, opts)
+		if err == nil && spec.async {
+			err = errors.New("pre-hooks cannot run in the background")
+		}
+		if err != nil {
+			return &HookError{Hook: hook, Failure: HookFailureAbort, Err: err}
+		}
+		var stderr bytes.Buffer
+		if err := runHook(ctx, spec, dir, env, w, &stderr); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" && !errors.Is(err, errHookTimedOut) {
+				err = errors.New(msg)
+			}
+			return &HookError{Hook: hook, Failure: HookFailureAbort, Err: err}
 		}
 	}
 	return nil
@@ -0,0 +1,160 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHook(t *testing.T) {
+	defaults := HookOptions{Timeout: time.Minute, Failure: HookFailureAbort}
+	tests := []struct {
+		name    string
+		hook    string
+		want    hookSpec
+		wantErr bool
+	}{
+		{
+			name: "plain command",
+			hook: "npm ci",
+			want: hookSpec{command: "npm ci", timeout: time.Minute, failure: HookFailureAbort},
+		},
+		{
+			name: "shell test is not an option prefix",
+			hook: "[ -f package.json ] && npm ci",
+			want: hookSpec{command: "[ -f package.json ] && npm ci", timeout: time.Minute, failure: HookFailureAbort},
+		},
+		{
+			name: "timeout",
+			hook: "[timeout=30s] npm ci",
+			want: hookSpec{command: "npm ci", timeout: 30 * time.Second, failure: HookFailureAbort},
+		},
+		{
+			name: "timeout and failure",
+			hook: "[timeout=0,failure=warn] npm ci",
+			want: hookSpec{command: "npm ci", timeout: 0, failure: HookFailureWarn},
+		},
+		{
+			name: "async",
+			hook: "[async,timeout=10m] npm ci",
+			want: hookSpec{command: "npm ci", timeout: 10 * time.Minute, failure: HookFailureAbort, async: true},
+		},
+		{
+			name:    "async with a value",
+			hook:    "[async=yes] npm ci",
+			wantErr: true,
+		},
+		{
+			name:    "unknown option",
+			hook:    "[timout=30s] npm ci",
+			wantErr: true,
+		},
+		{
+			name:    "invalid timeout",
+			hook:    "[timeout=soon] npm ci",
+			wantErr: true,
+		},
+		{
+			name:    "invalid failure policy",
+			hook:    "[failure=ignore] npm ci",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHook(tt.hook, defaults)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseHook(%q) should fail, got %+v", tt.hook, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseHook(%q) error: %v", tt.hook, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseHook(%q) = %+v, want %+v", tt.hook, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_HookOptions(t *testing.T) {
+	opts, err := Config{}.HookOptions()
+	if err != nil {
+		t.Fatalf("HookOptions() error: %v", err)
+	}
+	if opts != (HookOptions{Failure: HookFailureAbort}) {
+		t.Errorf("HookOptions() default = %+v", opts)
+	}
+
+	opts, err = Config{HookTimeout: "5m", HookFailure: "rollback"}.HookOptions()
+	if err != nil {
+		t.Fatalf("HookOptions() error: %v", err)
+	}
+	if opts != (HookOptions{Timeout: 5 * time.Minute, Failure: HookFailureRollback}) {
+		t.Errorf("HookOptions() = %+v", opts)
+	}
+
+	if _, err := (Config{HookTimeout: "-1s"}).HookOptions(); err == nil {
+		t.Error("HookOptions() should fail for a negative timeout")
+	}
+	if _, err := (Config{HookFailure: "ignore"}).HookOptions(); err == nil {
+		t.Error("HookOptions() should fail for an unknown failure policy")
+	}
+}
+
+func TestRunHooks_Failure(t *testing.T) {
+	dir := t.TempDir()
+	hooks := []string{"[failure=warn] exit 1", "touch ran"}
+
+	var out bytes.Buffer
+	if err := RunHooks(t.Context(), hooks, dir, HookEnv{}, HookOptions{}, &out); err != nil {
+		t.Fatalf("RunHooks() should not fail for a warn hook: %v", err)
+	}
+	if !strings.Contains(out.String(), "warning: hook") {
+		t.Errorf("output should contain a warning, got: %q", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ran")); err != nil {
+		t.Error("hooks after a warn hook should still run")
+	}
+
+	err := RunHooks(t.Context(), []string{"exit 1", "touch never"}, dir, HookEnv{}, HookOptions{Failure: HookFailureRollback}, &out)
+	var herr *HookError
+	if !errors.As(err, &herr) || herr.Failure != HookFailureRollback {
+		t.Fatalf("RunHooks() error = %v, want a *HookError with the rollback policy", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "never")); !os.IsNotExist(err) {
+		t.Error("hooks after a failed hook should not run")
+	}
+}
+
+func TestRunHooks_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are not used on Windows")
+	}
+	dir := t.TempDir()
+	// The background sleep is in the same process group and must be killed
+	// too, or it would touch the file after the timeout
+	hooks := []string{"(sleep 1 && touch leaked) & sleep 30"}
+
+	start := time.Now()
+	var out bytes.Buffer
+	err := RunHooks(t.Context(), hooks, dir, HookEnv{}, HookOptions{Timeout: 200 * time.Millisecond}, &out)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 200ms") {
+		t.Fatalf("RunHooks() error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("RunHooks() took %s, the hook should have been killed", elapsed)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat(filepath.Join(dir, "leaked")); !os.IsNotExist(err) {
+		t.Error("processes started by a timed-out hook should be killed")
+	}
+}
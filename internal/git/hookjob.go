@@ -0,0 +1,338 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// hookJobDirName is the directory under the git common dir that holds the
+	// status and log of background hooks, one subdirectory per worktree.
+	hookJobDirName = "wt-hooks"
+	// hookJobMetaFile is the status file of a background hook job.
+	hookJobMetaFile = "job.json"
+	// hookJobLogFile is the log of a background hook job.
+	hookJobLogFile = "hooks.log"
+	// hookJobMainName names the job directory of the main worktree, which has
+	// no administrative directory under <common>/worktrees.
+	hookJobMainName = "@main"
+
+	// HookJobRunnerFlag is the hidden git-wt flag that runs a background hook
+	// job. git-wt re-executes itself with it so that the job outlives the
+	// command that started it.
+	HookJobRunnerFlag = "run-hook-job"
+)
+
+// Background hook job statuses.
+const (
+	HookJobRunning   = "running"
+	HookJobSucceeded = "succeeded"
+	HookJobFailed    = "failed"
+)
+
+// HookJob is a set of async hooks running in the background for a worktree.
+//
+// Jobs are kept in <common>/wt-hooks/<name>, where name is the name of the
+// worktree's administrative directory, so that a job follows its worktree
+// when it is moved. Each worktree keeps only its latest job.
+type HookJob struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	Env        HookEnv     `json:"env"`
+	Hooks      []string    `json:"hooks"`
+	Options    HookOptions `json:"options"`
+	PID        int         `json:"pid,omitempty"` // PID of the runner, set once it has started
+	Status     string      `json:"status"`
+	Error      string      `json:"error,omitempty"`
+	StartedAt  time.Time   `json:"started_at"`
+	FinishedAt time.Time   `json:"finished_at,omitzero"`
+	Log        string      `json:"log"`
+
+	dir string // Directory of the job
+}
+
+// hookJobDir returns the job directory of the worktree at wtPath.
+func hookJobDir(ctx context.Context, wtPath string) (string, error) {
+	gitDir, err := gitOutput(ctx, nil, "-C", wtPath, "rev-parse", "--path-format=absolute", "--git-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git directory of %s: %w", wtPath, err)
+	}
+	commonDir, err := gitOutput(ctx, nil, "-C", wtPath, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git common directory of %s: %w", wtPath, err)
+	}
+	name := filepath.Base(gitDir)
+	if filepath.Clean(gitDir) == filepath.Clean(commonDir) {
+		name = hookJobMainName
+	}
+	return filepath.Join(commonDir, hookJobDirName, name), nil
+}
+
+// startHookJob starts hooks in a detached git-wt process running in dir. The
+// output of the hooks goes to the job log, so that the shell integration does
+// not wait for them.
+func startHookJob(ctx context.Context, hooks []string, dir string, env HookEnv, opts HookOptions) (*HookJob, error) {
+	jobDir, err := hookJobDir(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(jobDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create hook job directory: %w", err)
+	}
+
+	now := time.Now()
+	job := &HookJob{
+		ID:        strconv.FormatInt(now.UnixNano(), 36),
+		Name:      filepath.Base(jobDir),
+		Env:       env,
+		Hooks:     hooks,
+		Options:   opts,
+		Status:    HookJobRunning,
+		StartedAt: now,
+		Log:       filepath.Join(jobDir, hookJobLogFile),
+		dir:       jobDir,
+	}
+
+	// Keep the log of a job that is still running (its runner appends to it);
+	// otherwise start a fresh one
+	flag := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if prev, err := readHookJob(jobDir); err != nil || prev.Status != HookJobRunning {
+		flag |= os.O_TRUNC
+	}
+	logFile, err := os.OpenFile(job.Log, flag, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hook log: %w", err)
+	}
+	defer logFile.Close()
+	if err := writeHookJob(job); err != nil {
+		return nil, err
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate git-wt executable: %w", err)
+	}
+	// Not tied to ctx: the job must survive git-wt exiting or being
+	// interrupted
+	cmd := exec.Command(self, "--"+HookJobRunnerFlag, filepath.Join(jobDir, hookJobMetaFile)) // #nosec
+	cmd.Dir = dir
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detachHookJob(cmd)
+	if err := cmd.Start(); err != nil {
+		job.Status = HookJobFailed
+		job.Error = err.Error()
+		_ = writeHookJob(job) //nostyle:handlerrors
+		return nil, fmt.Errorf("failed to start background hooks: %w", err)
+	}
+	_ = cmd.Process.Release() //nostyle:handlerrors
+	return job, nil
+}
+
+// RunHookJob runs the background hook job described by file. It is the
+// entry point of the detached git-wt process started for async hooks; the
+// hooks write to its stdout/stderr, which is the job log.
+func RunHookJob(ctx context.Context, file string) error {
+	job, err := readHookJob(filepath.Dir(file))
+	if err != nil {
+		return err
+	}
+	job.PID = os.Getpid()
+	if err := updateHookJob(job); err != nil {
+		return err
+	}
+
+	job.Status = HookJobSucceeded
+	for _, hook := range job.Hooks {
+		fmt.Fprintf(os.Stdout, "==> %s\n", hook)
+		spec, err := parseHook(hook, job.Options)
+		if err == nil {
+			err = runHook(ctx, spec, "", job.Env, os.Stdout, os.Stderr)
+		}
+		if err != nil {
+			herr := &HookError{Hook: hook, Failure: spec.failure, Err: err}
+			if spec.failure == HookFailureWarn {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", herr)
+				continue
+			}
+			job.Status = HookJobFailed
+			job.Error = herr.Error()
+			fmt.Fprintf(os.Stderr, "%v\n", herr)
+			break
+		}
+	}
+	job.FinishedAt = time.Now()
+	return updateHookJob(job)
+}
+
+// FindHookJob returns the latest background hook job of the worktree at
+// wtPath, or nil if it has none.
+func FindHookJob(ctx context.Context, wtPath string) (*HookJob, error) {
+	jobDir, err := hookJobDir(ctx, wtPath)
+	if err != nil {
+		return nil, err
+	}
+	job, err := readHookJob(jobDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// ListHookJobs returns the background hook jobs of the current repository,
+// newest first.
+func ListHookJobs(ctx context.Context) ([]*HookJob, error) {
+	_, commonDir, err := gitDirs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := os.ReadDir(filepath.Join(commonDir, hookJobDirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var jobs []*HookJob
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		job, err := readHookJob(filepath.Join(commonDir, hookJobDirName, d.Name()))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].StartedAt.After(jobs[j].StartedAt)
+	})
+	return jobs, nil
+}
+
+// HookJobDir returns the directory of the background hook job of the
+// worktree at wtPath. It is resolved through the worktree, so it has to be
+// called while the worktree still exists.
+func HookJobDir(ctx context.Context, wtPath string) (string, error) {
+	return hookJobDir(ctx, wtPath)
+}
+
+// RemoveHookJob removes the background hook job in jobDir (see HookJobDir),
+// e.g. after its worktree is deleted. A job that is still running keeps
+// running, but no longer records its status.
+func RemoveHookJob(jobDir string) error {
+	return os.RemoveAll(jobDir)
+}
+
+// Refresh re-reads the status of the job from disk.
+func (j *HookJob) Refresh() error {
+	job, err := readHookJob(j.dir)
+	if err != nil {
+		return err
+	}
+	*j = *job
+	return nil
+}
+
+// readHookJob reads the job in jobDir. A job whose runner is gone without
+// recording a result is reported as failed.
+func readHookJob(jobDir string) (*HookJob, error) {
+	file := filepath.Join(jobDir, hookJobMetaFile)
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	job := &HookJob{}
+	if err := json.Unmarshal(b, job); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	job.dir = jobDir
+	if job.Status == HookJobRunning && job.PID != 0 && !processAlive(job.PID) {
+		job.Status = HookJobFailed
+		job.Error = "hook runner exited unexpectedly"
+	}
+	return job, nil
+}
+
+// writeHookJob writes the status file of job atomically.
+func writeHookJob(job *HookJob) error {
+	b, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(job.dir, hookJobMetaFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write hook job status: %w", err)
+	}
+	_, err = tmp.Write(append(b, '\n'))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(job.dir, hookJobMetaFile))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name()) //nostyle:handlerrors
+		return fmt.Errorf("failed to write hook job status: %w", err)
+	}
+	return nil
+}
+
+// updateHookJob writes job unless it has been replaced by a newer job for
+// the same worktree or removed together with the worktree.
+func updateHookJob(job *HookJob) error {
+	cur, err := readHookJob(job.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if cur.ID != job.ID {
+		return nil
+	}
+	return writeHookJob(job)
+}
+
+// FollowLog copies the log of the job to w until the job is no longer
+// running or ctx is done. It returns the final state of the job.
+func (j *HookJob) FollowLog(ctx context.Context, w io.Writer) error {
+	f, err := os.Open(j.Log)
+	if err != nil {
+		return fmt.Errorf("failed to open hook log: %w", err)
+	}
+	defer f.Close()
+	for {
+		if _, err := io.Copy(w, f); err != nil {
+			return err
+		}
+		if err := j.Refresh(); err != nil {
+			return err
+		}
+		if j.Status != HookJobRunning {
+			// Pick up what was written after the last copy
+			_, err := io.Copy(w, f)
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package git
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestReadHookJob(t *testing.T) {
+	dir := t.TempDir()
+	job := &HookJob{
+		ID:        "1",
+		Name:      "feature",
+		Env:       HookEnv{Action: HookActionCreate, Branch: "feature", Path: "/path/to/feature"},
+		Hooks:     []string{"[async] npm ci"},
+		Status:    HookJobRunning,
+		StartedAt: time.Now(),
+		dir:       dir,
+	}
+	if err := writeHookJob(job); err != nil {
+		t.Fatalf("writeHookJob() error: %v", err)
+	}
+
+	// Not started yet: still running
+	got, err := readHookJob(dir)
+	if err != nil {
+		t.Fatalf("readHookJob() error: %v", err)
+	}
+	if got.Status != HookJobRunning || got.Env.Branch != "feature" {
+		t.Errorf("readHookJob() = %+v", got)
+	}
+
+	// The runner is alive
+	job.PID = os.Getpid()
+	if err := updateHookJob(job); err != nil {
+		t.Fatalf("updateHookJob() error: %v", err)
+	}
+	if got, _ := readHookJob(dir); got.Status != HookJobRunning {
+		t.Errorf("status = %q, want %q", got.Status, HookJobRunning)
+	}
+
+	// A newer job replaced this one: updates of the old runner are dropped
+	newer := *job
+	newer.ID = "2"
+	newer.PID = 0
+	if err := writeHookJob(&newer); err != nil {
+		t.Fatalf("writeHookJob() error: %v", err)
+	}
+	job.Status = HookJobFailed
+	if err := updateHookJob(job); err != nil {
+		t.Fatalf("updateHookJob() error: %v", err)
+	}
+	if got, _ := readHookJob(dir); got.ID != "2" || got.Status != HookJobRunning {
+		t.Errorf("readHookJob() = %+v, want the newer job", got)
+	}
+}
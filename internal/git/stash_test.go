@@ -0,0 +1,142 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestWorktreeStash(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	wtPath := filepath.Join(repo.ParentDir(), "wt-feature")
+	repo.Git("worktree", "add", "-b", "feature", wtPath)
+	if err := os.WriteFile(filepath.Join(wtPath, "README.md"), []byte("# Modified"), 0600); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wtPath, "untracked.txt"), []byte("untracked"), 0600); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	head := strings.TrimSpace(repo.Git("rev-parse", "feature"))
+
+	restore := repo.Chdir()
+	defer restore()
+
+	if err := SaveWorktreeStash(t.Context(), wtPath, "feature"); err != nil {
+		t.Fatalf("SaveWorktreeStash() error: %v", err)
+	}
+
+	st, err := GetWorktreeStatus(t.Context(), wtPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.Dirty() {
+		t.Errorf("worktree should be clean after stashing, got %+v", st)
+	}
+	if list := repo.Git("stash", "list"); list != "" {
+		t.Errorf("stash list should be left untouched, got %q", list)
+	}
+
+	branches, err := ListWorktreeStashes(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(branches) != 1 || branches[0] != "feature" {
+		t.Errorf("ListWorktreeStashes() = %v, want [feature]", branches)
+	}
+
+	base, err := WorktreeStashBase(t.Context(), "feature")
+	if err != nil {
+		t.Fatalf("WorktreeStashBase() error: %v", err)
+	}
+	if base != head {
+		t.Errorf("WorktreeStashBase() = %q, want %q", base, head)
+	}
+
+	// A second stash for the same branch is refused
+	if err := os.WriteFile(filepath.Join(wtPath, "other.txt"), []byte("other"), 0600); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	if err := SaveWorktreeStash(t.Context(), wtPath, "feature"); err == nil {
+		t.Error("SaveWorktreeStash() should fail when a stash already exists")
+	}
+	if err := os.Remove(filepath.Join(wtPath, "other.txt")); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+
+	if err := ApplyWorktreeStash(t.Context(), wtPath, "feature"); err != nil {
+		t.Fatalf("ApplyWorktreeStash() error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(wtPath, "README.md"))
+	if err != nil || string(content) != "# Modified" {
+		t.Errorf("README.md = %q, %v; want restored modification", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(wtPath, "untracked.txt")); err != nil {
+		t.Errorf("untracked file should be restored: %v", err)
+	}
+
+	exists, err := WorktreeStashExists(t.Context(), "feature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exists {
+		t.Error("stash ref should be deleted after applying")
+	}
+	if _, err := WorktreeStashBase(t.Context(), "feature"); err == nil {
+		t.Error("WorktreeStashBase() should fail without a stash")
+	}
+}
+
+func TestSaveWorktreeStash_KeepsUserStashes(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	// A stash of the user's own, unrelated to git-wt
+	repo.CreateFile("README.md", "# User change")
+	repo.Git("stash", "push", "-m", "user stash")
+	userStash := strings.TrimSpace(repo.Git("rev-parse", "stash@{0}"))
+
+	wtPath := filepath.Join(repo.ParentDir(), "wt-feature")
+	repo.Git("worktree", "add", "-b", "feature", wtPath)
+
+	restore := repo.Chdir()
+	defer restore()
+
+	// Nothing to stash: git stash push succeeds without creating an entry
+	if err := SaveWorktreeStash(t.Context(), wtPath, "feature"); err == nil {
+		t.Error("SaveWorktreeStash() should fail when git stash saves nothing")
+	}
+	exists, err := WorktreeStashExists(t.Context(), "feature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exists {
+		t.Error("the user's stash should not be saved for the worktree")
+	}
+	if got := strings.TrimSpace(repo.Git("rev-parse", "stash@{0}")); got != userStash {
+		t.Errorf("stash@{0} = %s, want the user's stash %s", got, userStash)
+	}
+
+	// With changes, only the new entry is moved off the stash list
+	if err := os.WriteFile(filepath.Join(wtPath, "untracked.txt"), []byte("untracked"), 0600); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	if err := SaveWorktreeStash(t.Context(), wtPath, "feature"); err != nil {
+		t.Fatalf("SaveWorktreeStash() error: %v", err)
+	}
+	if got := strings.TrimSpace(repo.Git("rev-parse", "stash@{0}")); got != userStash {
+		t.Errorf("stash@{0} = %s, want the user's stash %s", got, userStash)
+	}
+	if got := strings.TrimSpace(repo.Git("rev-parse", WorktreeStashRef("feature"))); got == userStash {
+		t.Error("the user's stash should not be saved for the worktree")
+	}
+	if list := repo.Git("stash", "list"); strings.Count(list, "\n") > 1 || !strings.Contains(list, "user stash") {
+		t.Errorf("stash list should only contain the user's stash, got %q", list)
+	}
+}
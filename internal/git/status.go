@@ -0,0 +1,105 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// WorktreeStatus holds the working tree and upstream state of a worktree.
+type WorktreeStatus struct {
+	Modified  int    // Number of tracked files with staged or unstaged changes
+	Untracked int    // Number of untracked files (not ignored)
+	Upstream  string // Upstream branch name (e.g., origin/main), empty if not set
+	Ahead     int    // Number of commits ahead of the upstream
+	Behind    int    // Number of commits behind the upstream
+}
+
+// Dirty reports whether the worktree has modified or untracked files.
+func (s WorktreeStatus) Dirty() bool {
+	return s.Modified > 0 || s.Untracked > 0
+}
+
+// GetWorktreeStatus returns the status of the worktree at path using
+// 'git status --porcelain=v2 --branch'.
+func GetWorktreeStatus(ctx context.Context, path string) (*WorktreeStatus, error) {
+	cmd, err := gitCommand(ctx, "status", "--porcelain=v2", "--branch", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = path
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseStatusPorcelainV2(string(out))
+}
+
+// ListWorktreeStatuses returns the status of each worktree, in the same order
+// as the given slice. Statuses are computed concurrently with at most
+// runtime.NumCPU() git processes at a time. The entry is nil for bare entries
+// and for worktrees whose status cannot be determined (e.g., the directory
+// has been removed).
+func ListWorktreeStatuses(ctx context.Context, worktrees []Worktree) []*WorktreeStatus {
+	statuses := make([]*WorktreeStatus, len(worktrees))
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i, wt := range worktrees {
+		if wt.Bare {
+			continue
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			st, err := GetWorktreeStatus(ctx, wt.Path)
+			if err != nil {
+				return
+			}
+			statuses[i] = st
+		})
+	}
+	wg.Wait()
+	return statuses
+}
+
+// parseStatusPorcelainV2 parses the output of 'git status --porcelain=v2 --branch'.
+func parseStatusPorcelainV2(out string) (*WorktreeStatus, error) {
+	st := &WorktreeStatus{}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "# branch.upstream "):
+			st.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			// Format: "# branch.ab +<ahead> -<behind>"
+			fields := strings.Fields(strings.TrimPrefix(line, "# branch.ab "))
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("unexpected branch.ab line: %q", line)
+			}
+			ahead, err := strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+			if err != nil {
+				return nil, fmt.Errorf("unexpected branch.ab line: %q: %w", line, err)
+			}
+			behind, err := strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			if err != nil {
+				return nil, fmt.Errorf("unexpected branch.ab line: %q: %w", line, err)
+			}
+			st.Ahead = ahead
+			st.Behind = behind
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "), strings.HasPrefix(line, "u "):
+			// Ordinary, renamed/copied, and unmerged entries
+			st.Modified++
+		case strings.HasPrefix(line, "? "):
+			st.Untracked++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
@@ -0,0 +1,121 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestGetWorktreeStatus(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile("other.txt", "other")
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	st, err := GetWorktreeStatus(t.Context(), repo.Root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.Dirty() {
+		t.Errorf("GetWorktreeStatus() on clean repo = %+v, want clean", st)
+	}
+	if st.Upstream != "" {
+		t.Errorf("GetWorktreeStatus().Upstream = %q, want empty", st.Upstream)
+	}
+
+	repo.CreateFile("README.md", "# Modified")
+	repo.CreateFile("other.txt", "modified")
+	repo.CreateFile("untracked.txt", "untracked")
+	repo.CreateFile("dir/untracked.txt", "untracked")
+
+	st, err = GetWorktreeStatus(t.Context(), repo.Root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.Modified != 2 {
+		t.Errorf("GetWorktreeStatus().Modified = %d, want 2", st.Modified)
+	}
+	if st.Untracked != 2 {
+		t.Errorf("GetWorktreeStatus().Untracked = %d, want 2", st.Untracked)
+	}
+}
+
+func TestGetWorktreeStatus_Upstream(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("branch", "base")
+	repo.CreateFile("a.txt", "a")
+	repo.Commit("ahead 1")
+	repo.CreateFile("b.txt", "b")
+	repo.Commit("ahead 2")
+	repo.Git("checkout", "-b", "tracking", "base")
+	repo.CreateFile("c.txt", "c")
+	repo.Commit("ahead of main")
+	repo.Git("branch", "--set-upstream-to=main")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	st, err := GetWorktreeStatus(t.Context(), repo.Root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.Upstream != "main" {
+		t.Errorf("GetWorktreeStatus().Upstream = %q, want %q", st.Upstream, "main")
+	}
+	if st.Ahead != 1 {
+		t.Errorf("GetWorktreeStatus().Ahead = %d, want 1", st.Ahead)
+	}
+	if st.Behind != 2 {
+		t.Errorf("GetWorktreeStatus().Behind = %d, want 2", st.Behind)
+	}
+}
+
+func TestListWorktreeStatuses(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	wtPath := filepath.Join(repo.ParentDir(), "wt-feature")
+	repo.Git("worktree", "add", "-b", "feature", wtPath)
+	if err := os.WriteFile(filepath.Join(wtPath, "untracked.txt"), []byte("x"), 0600); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	missingPath := filepath.Join(repo.ParentDir(), "wt-missing")
+	repo.Git("worktree", "add", "-b", "missing", missingPath)
+	if err := os.RemoveAll(missingPath); err != nil {
+		t.Fatalf("failed to remove worktree directory: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	worktrees := []Worktree{
+		{Path: repo.Root, Branch: "main"},
+		{Path: wtPath, Branch: "feature"},
+		{Path: missingPath, Branch: "missing"},
+		{Path: repo.Root, Bare: true},
+	}
+	statuses := ListWorktreeStatuses(t.Context(), worktrees)
+	if len(statuses) != len(worktrees) {
+		t.Fatalf("ListWorktreeStatuses() returned %d entries, want %d", len(statuses), len(worktrees))
+	}
+	if statuses[0] == nil || statuses[0].Dirty() {
+		t.Errorf("main worktree status = %+v, want clean", statuses[0])
+	}
+	if statuses[1] == nil || statuses[1].Untracked != 1 {
+		t.Errorf("feature worktree status = %+v, want 1 untracked file", statuses[1])
+	}
+	if statuses[2] != nil {
+		t.Errorf("missing worktree status = %+v, want nil", statuses[2])
+	}
+	if statuses[3] != nil {
+		t.Errorf("bare entry status = %+v, want nil", statuses[3])
+	}
+}
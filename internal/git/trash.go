@@ -0,0 +1,311 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// trashDirName is the directory under the git common dir that holds
+	// soft-deleted worktrees.
+	trashDirName = "wt-trash"
+	// trashRefPrefix is the ref namespace that keeps the commits of
+	// soft-deleted worktrees reachable after their branch is deleted.
+	trashRefPrefix = "refs/wt-trash/"
+	// trashMetaFile is the metadata file of a trash entry.
+	trashMetaFile = "meta.json"
+)
+
+// TrashEntry describes a soft-deleted worktree.
+//
+// The worktree directory is kept in <entry>/worktree and its administrative
+// directory (<common>/worktrees/<name>) in <entry>/admin, so that git no
+// longer sees the worktree and its branch can be deleted. A commit of HEAD,
+// the index and the modified files (as created by 'git stash create', or HEAD
+// itself for a clean worktree) is kept under refs/wt-trash/<id>, so that gc
+// does not prune staged objects while the index is out of its sight.
+type TrashEntry struct {
+	ID        string    `json:"id"`
+	Branch    string    `json:"branch,omitempty"` // Empty for a detached HEAD
+	Path      string    `json:"path"`             // Original worktree path
+	Head      string    `json:"head"`             // HEAD commit when deleted
+	AdminName string    `json:"admin_name"`       // Name of the administrative directory under <common>/worktrees
+	DeletedAt time.Time `json:"deleted_at"`
+
+	dir string // Directory of the entry in the trash
+}
+
+// Ref returns the ref that keeps the HEAD commit and the index of the entry
+// reachable.
+func (e *TrashEntry) Ref() string {
+	return trashRefPrefix + e.ID
+}
+
+// Trash manages soft-deleted worktrees of a repository.
+type Trash struct {
+	commonDir string
+	dir       string
+}
+
+// OpenTrash returns the trash of the current repository. Operations on the
+// returned Trash do not depend on the current directory, so it stays usable
+// after the current worktree has been moved to the trash.
+func OpenTrash(ctx context.Context) (*Trash, error) {
+	_, commonDir, err := gitDirs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Trash{
+		commonDir: commonDir,
+		dir:       filepath.Join(commonDir, trashDirName),
+	}, nil
+}
+
+// git runs git against the repository of the trash.
+func (t *Trash) git(ctx context.Context, args ...string) (string, error) {
+	return gitOutput(ctx, nil, append([]string{"--git-dir", t.commonDir}, args...)...)
+}
+
+// Add moves the worktree into the trash. name identifies the entry together
+// with a timestamp (typically the worktree directory name).
+func (t *Trash) Add(ctx context.Context, wt *Worktree, name string) (*TrashEntry, error) {
+	adminDir, err := gitOutput(ctx, nil, "-C", wt.Path, "rev-parse", "--path-format=absolute", "--git-dir")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve administrative directory: %w", err)
+	}
+	head, err := gitOutput(ctx, nil, "-C", wt.Path, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	// The stash commit has HEAD as its first parent
+	cmd, err := gitCommand(ctx, "-C", wt.Path, "stash", "create", "git-wt: trash "+name)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Env = append(os.Environ(), internalIdentityEnv...)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to save the index and modified files: %w", err)
+	}
+	snapshot := strings.TrimSpace(string(out))
+	if snapshot == "" {
+		snapshot = head
+	}
+
+	now := time.Now()
+	e := &TrashEntry{
+		ID:        now.UTC().Format("20060102-150405") + "-" + strings.ReplaceAll(name, "/", "-"),
+		Path:      wt.Path,
+		Head:      head,
+		AdminName: filepath.Base(adminDir),
+		DeletedAt: now,
+	}
+	if wt.Branch != DetachedMarker {
+		e.Branch = wt.Branch
+	}
+	e.dir = filepath.Join(t.dir, e.ID)
+	if _, err := os.Stat(e.dir); err == nil {
+		return nil, fmt.Errorf("trash entry %q already exists", e.ID)
+	}
+	if err := os.MkdirAll(e.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create trash entry: %w", err)
+	}
+	if err := writeTrashMeta(e); err != nil {
+		_ = os.RemoveAll(e.dir) //nostyle:handlerrors
+		return nil, err
+	}
+	if _, err := t.git(ctx, "update-ref", "-m", "git-wt: trash "+e.ID, e.Ref(), snapshot); err != nil {
+		_ = os.RemoveAll(e.dir) //nostyle:handlerrors
+		return nil, fmt.Errorf("failed to save HEAD and the index to %s: %w", e.Ref(), err)
+	}
+	rollback := func() {
+		_, _ = t.git(ctx, "update-ref", "-d", e.Ref()) //nostyle:handlerrors
+		_ = os.RemoveAll(e.dir)                        //nostyle:handlerrors
+	}
+
+	if err := os.Rename(wt.Path, filepath.Join(e.dir, "worktree")); err != nil {
+		rollback()
+		return nil, fmt.Errorf("failed to move worktree to %s (the trash must be on the same file system as the worktree): %w", e.dir, err)
+	}
+	if err := os.Rename(adminDir, filepath.Join(e.dir, "admin")); err != nil {
+		if rerr := os.Rename(filepath.Join(e.dir, "worktree"), wt.Path); rerr == nil {
+			rollback()
+		}
+		return nil, fmt.Errorf("failed to move administrative directory to %s: %w", e.dir, err)
+	}
+	return e, nil
+}
+
+// List returns the entries in the trash, newest first.
+func (t *Trash) List() ([]*TrashEntry, error) {
+	dirs, err := os.ReadDir(t.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []*TrashEntry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		dir := filepath.Join(t.dir, d.Name())
+		b, err := os.ReadFile(filepath.Join(dir, trashMetaFile))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		e := &TrashEntry{}
+		if err := json.Unmarshal(b, e); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, trashMetaFile), err)
+		}
+		e.dir = dir
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// Find returns the newest entry whose ID, branch or original directory name
+// matches name.
+func (t *Trash) Find(name string) (*TrashEntry, error) {
+	entries, err := t.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.ID == name || (e.Branch != "" && e.Branch == name) || filepath.Base(e.Path) == name {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("no worktree named %q in the trash", name)
+}
+
+// Restore moves the entry back to its original path and re-registers it with
+// 'git worktree repair'. A deleted branch is recreated at the saved HEAD.
+func (t *Trash) Restore(ctx context.Context, e *TrashEntry) error {
+	if _, err := os.Stat(e.Path); err == nil {
+		return fmt.Errorf("cannot restore %q: %s already exists", e.ID, e.Path)
+	}
+	adminDir := filepath.Join(t.commonDir, "worktrees", e.AdminName)
+	if _, err := os.Stat(adminDir); err == nil {
+		return fmt.Errorf("cannot restore %q: %s already exists", e.ID, adminDir)
+	}
+
+	if e.Branch != "" {
+		if _, err := t.git(ctx, "show-ref", "--verify", "--quiet", "refs/heads/"+e.Branch); err != nil {
+			if _, err := t.git(ctx, "branch", "--", e.Branch, e.Head); err != nil {
+				return fmt.Errorf("failed to recreate branch %q: %w", e.Branch, err)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(adminDir), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(e.dir, "admin"), adminDir); err != nil {
+		return fmt.Errorf("failed to restore administrative directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(e.Path), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(e.dir, "worktree"), e.Path); err != nil {
+		_ = os.Rename(adminDir, filepath.Join(e.dir, "admin")) //nostyle:handlerrors
+		return fmt.Errorf("failed to restore worktree directory: %w", err)
+	}
+
+	cmd, err := gitCommand(ctx, "--git-dir", t.commonDir, "worktree", "repair", e.Path)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git worktree repair failed: %w", err)
+	}
+
+	return t.Purge(ctx, e)
+}
+
+// Purge permanently deletes the entry and its saved ref.
+func (t *Trash) Purge(ctx context.Context, e *TrashEntry) error {
+	if err := os.RemoveAll(e.dir); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", e.dir, err)
+	}
+	if _, err := t.git(ctx, "update-ref", "-d", e.Ref()); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", e.Ref(), err)
+	}
+	return nil
+}
+
+// PurgeExpired purges the entries deleted more than expiry before now and
+// returns them. An expiry of zero never expires entries.
+func (t *Trash) PurgeExpired(ctx context.Context, expiry time.Duration, now time.Time) ([]*TrashEntry, error) {
+	if expiry <= 0 {
+		return nil, nil
+	}
+	entries, err := t.List()
+	if err != nil {
+		return nil, err
+	}
+	var purged []*TrashEntry
+	for _, e := range entries {
+		if now.Sub(e.DeletedAt) < expiry {
+			continue
+		}
+		if err := t.Purge(ctx, e); err != nil {
+			return purged, err
+		}
+		purged = append(purged, e)
+	}
+	return purged, nil
+}
+
+func writeTrashMeta(e *TrashEntry) error {
+	b, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(e.dir, trashMetaFile), append(b, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write trash metadata: %w", err)
+	}
+	return nil
+}
+
+// ParseExpiry parses a trash expiry such as "30d", "12h" or "90m". A day
+// suffix ("d") is accepted in addition to time.ParseDuration units. "0" and
+// "never" disable expiry.
+func ParseExpiry(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "0" || s == "never" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid expiry %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid expiry %q: %w", s, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid expiry %q: must not be negative", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,210 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestTrash(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	wtPath := filepath.Join(repo.ParentDir(), "wt-feature")
+	repo.Git("worktree", "add", "-b", "feature", wtPath)
+	if err := os.WriteFile(filepath.Join(wtPath, "experiment.txt"), []byte("experiment"), 0600); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	repo.Git("-C", wtPath, "add", ".")
+	repo.Git("-C", wtPath, "commit", "-m", "experiment")
+	head := strings.TrimSpace(repo.Git("rev-parse", "feature"))
+
+	restore := repo.Chdir()
+	defer restore()
+
+	trash, err := OpenTrash(t.Context())
+	if err != nil {
+		t.Fatalf("OpenTrash() error: %v", err)
+	}
+	e, err := trash.Add(t.Context(), &Worktree{Path: wtPath, Branch: "feature"}, "feature")
+	if err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Errorf("worktree directory should be moved to the trash")
+	}
+	if list := repo.Git("worktree", "list"); strings.Contains(list, "wt-feature") {
+		t.Errorf("worktree should no longer be registered, got:\n%s", list)
+	}
+
+	// The branch can be deleted while its commits stay reachable
+	repo.Git("branch", "-D", "feature")
+	if got := strings.TrimSpace(repo.Git("rev-parse", e.Ref())); got != head {
+		t.Errorf("%s = %q, want %q", e.Ref(), got, head)
+	}
+
+	entries, err := trash.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != e.ID || entries[0].Branch != "feature" || entries[0].Path != wtPath {
+		t.Fatalf("List() = %+v, want the trashed worktree", entries)
+	}
+
+	found, err := trash.Find("feature")
+	if err != nil {
+		t.Fatalf("Find() error: %v", err)
+	}
+	if err := trash.Restore(t.Context(), found); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(wtPath, "experiment.txt")); err != nil {
+		t.Errorf("worktree should be restored: %v", err)
+	}
+	if list := repo.Git("worktree", "list"); !strings.Contains(list, "wt-feature") || !strings.Contains(list, "[feature]") {
+		t.Errorf("worktree should be registered again, got:\n%s", list)
+	}
+	if got := strings.TrimSpace(repo.Git("-C", wtPath, "status", "--porcelain")); got != "" {
+		t.Errorf("restored worktree should be clean, got:\n%s", got)
+	}
+	entries, err = trash.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("trash should be empty after restoring, got %+v", entries)
+	}
+	if refs := repo.Git("for-each-ref", "refs/wt-trash/"); refs != "" {
+		t.Errorf("trash ref should be deleted after restoring, got: %s", refs)
+	}
+}
+
+func TestTrash_KeepsStagedObjects(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	wtPath := filepath.Join(repo.ParentDir(), "wt-staged")
+	repo.Git("worktree", "add", "-b", "staged", wtPath)
+	if err := os.WriteFile(filepath.Join(wtPath, "staged.txt"), []byte("staged only"), 0600); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	repo.Git("-C", wtPath, "add", "staged.txt")
+	blob := strings.TrimSpace(repo.Git("-C", wtPath, "rev-parse", ":staged.txt"))
+	head := strings.TrimSpace(repo.Git("rev-parse", "staged"))
+
+	restore := repo.Chdir()
+	defer restore()
+
+	trash, err := OpenTrash(t.Context())
+	if err != nil {
+		t.Fatalf("OpenTrash() error: %v", err)
+	}
+	e, err := trash.Add(t.Context(), &Worktree{Path: wtPath, Branch: "staged"}, "staged")
+	if err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	// The index of the trashed worktree is out of gc's sight
+	repo.Git("branch", "-D", "staged")
+	repo.Git("prune", "--expire=now")
+	if _, err := repo.GitE("cat-file", "-e", blob); err != nil {
+		t.Fatalf("staged blob %s should stay reachable from %s: %v", blob, e.Ref(), err)
+	}
+
+	if err := trash.Restore(t.Context(), e); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+	if got := strings.TrimSpace(repo.Git("rev-parse", "staged")); got != head {
+		t.Errorf("restored branch = %q, want %q", got, head)
+	}
+	if got := strings.TrimSpace(repo.Git("-C", wtPath, "status", "--porcelain")); got != "A  staged.txt" {
+		t.Errorf("restored worktree should have staged.txt staged, got:\n%s", got)
+	}
+}
+
+func TestTrash_PurgeExpired(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	wtPath := filepath.Join(repo.ParentDir(), "wt-old")
+	repo.Git("worktree", "add", "-b", "old", wtPath)
+
+	restore := repo.Chdir()
+	defer restore()
+
+	trash, err := OpenTrash(t.Context())
+	if err != nil {
+		t.Fatalf("OpenTrash() error: %v", err)
+	}
+	if _, err := trash.Add(t.Context(), &Worktree{Path: wtPath, Branch: "old"}, "old"); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	purged, err := trash.PurgeExpired(t.Context(), time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("PurgeExpired() error: %v", err)
+	}
+	if len(purged) != 0 {
+		t.Errorf("PurgeExpired() purged %d entries before expiry, want 0", len(purged))
+	}
+	purged, err = trash.PurgeExpired(t.Context(), 0, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeExpired() error: %v", err)
+	}
+	if len(purged) != 0 {
+		t.Errorf("PurgeExpired() with zero expiry purged %d entries, want 0", len(purged))
+	}
+
+	purged, err = trash.PurgeExpired(t.Context(), time.Hour, time.Now().Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeExpired() error: %v", err)
+	}
+	if len(purged) != 1 {
+		t.Fatalf("PurgeExpired() purged %d entries, want 1", len(purged))
+	}
+	entries, err := trash.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("trash should be empty after purging, got %+v", entries)
+	}
+	if refs := repo.Git("for-each-ref", "refs/wt-trash/"); refs != "" {
+		t.Errorf("trash ref should be deleted after purging, got: %s", refs)
+	}
+}
+
+func TestParseExpiry(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"0", 0, false},
+		{"never", 0, false},
+		{"-1h", 0, true},
+		{"xd", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseExpiry(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseExpiry(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseExpiry(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
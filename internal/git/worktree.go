@@ -18,10 +18,14 @@ const DetachedMarker = "[detached]"
 
 // Worktree represents a git worktree.
 type Worktree struct {
-	Path   string
-	Branch string
-	Head   string
-	Bare   bool
+	Path           string
+	Branch         string
+	Head           string
+	Bare           bool
+	Locked         bool
+	LockReason     string // Empty if locked without a reason
+	Prunable       bool   // True if the worktree directory no longer exists
+	PrunableReason string
 }
 
 // ListWorktrees returns a list of all worktrees.
@@ -68,6 +72,12 @@ func ListWorktrees(ctx context.Context) ([]Worktree, error) {
 			current.Bare = true
 		case line == "detached":
 			current.Branch = DetachedMarker
+		case line == "locked", strings.HasPrefix(line, "locked "):
+			current.Locked = true
+			current.LockReason = strings.TrimPrefix(strings.TrimPrefix(line, "locked"), " ")
+		case line == "prunable", strings.HasPrefix(line, "prunable "):
+			current.Prunable = true
+			current.PrunableReason = strings.TrimPrefix(strings.TrimPrefix(line, "prunable"), " ")
 		}
 	}
 
@@ -281,7 +291,31 @@ type addWorktreeContext struct {
 
 // prepareAdd detects the repository type (bare vs normal), determines the
 // copy source worktree root, and initializes the destination parent directory.
-func prepareAdd(ctx context.Context, path string) (*addWorktreeContext, error) {
+func prepareAdd(ctx context.Context, path string, copyOpts CopyOptions) (*addWorktreeContext, error) {
+	ac, err := resolveCopySource(ctx, copyOpts.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	parentDir := filepath.Dir(path)
+	if err := os.MkdirAll(parentDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	if err := initBaseDir(parentDir); err != nil {
+		return nil, err
+	}
+
+	return ac, nil
+}
+
+// resolveCopySource detects the repository type (bare vs normal) and
+// determines the copy source worktree root: source if it is not empty, or
+// the current worktree.
+func resolveCopySource(ctx context.Context, source string) (*addWorktreeContext, error) {
+	if source != "" {
+		return &addWorktreeContext{srcRoot: source}, nil
+	}
+
 	isBareRoot, err := IsBareRoot(ctx)
 	if err != nil {
 		return nil, err
@@ -295,22 +329,15 @@ func prepareAdd(ctx context.Context, path string) (*addWorktreeContext, error) {
 		}
 	}
 
-	parentDir := filepath.Dir(path)
-	if err := os.MkdirAll(parentDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create parent directory: %w", err)
-	}
-	if err := initBaseDir(parentDir); err != nil {
-		return nil, err
-	}
-
 	return &addWorktreeContext{isBareRoot: isBareRoot, srcRoot: srcRoot}, nil
 }
 
-// copyAfterAdd copies files from the current worktree to the newly created worktree.
-// It is a no-op when running from a bare root (no working tree to copy from).
-func copyAfterAdd(ctx context.Context, ac *addWorktreeContext, dstPath string, copyOpts CopyOptions) error {
+// planAddCopy computes the files to copy from the source worktree to a new
+// worktree at dstPath. It returns an empty plan when running from a bare root
+// without a source (no working tree to copy from).
+func planAddCopy(ctx context.Context, ac *addWorktreeContext, dstPath string, copyOpts CopyOptions) (*CopyPlan, error) {
 	if ac.isBareRoot {
-		return nil
+		return &CopyPlan{}, nil
 	}
 
 	// Exclude basedir from copy to prevent circular copying, but only when
@@ -326,38 +353,62 @@ func copyAfterAdd(ctx context.Context, ac *addWorktreeContext, dstPath string, c
 		copyOpts.ExcludeDirs = append(copyOpts.ExcludeDirs, parentDir)
 	}
 
-	if err := CopyFilesToWorktree(ctx, ac.srcRoot, dstPath, copyOpts, os.Stderr); err != nil {
-		return fmt.Errorf("failed to copy files: %w", err)
+	return PlanCopy(ctx, ac.srcRoot, copyOpts)
+}
+
+// PlanAddCopy computes the files AddWorktree and AddWorktreeWithNewBranch
+// would copy or symlink into a new worktree at path, without creating it.
+func PlanAddCopy(ctx context.Context, path string, copyOpts CopyOptions) (*CopyPlan, error) {
+	ac, err := resolveCopySource(ctx, copyOpts.Source)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return planAddCopy(ctx, ac, path, copyOpts)
 }
 
-// AddWorktree creates a new worktree for the given branch.
-func AddWorktree(ctx context.Context, path, branch string, copyOpts CopyOptions) error {
-	ac, err := prepareAdd(ctx, path)
+// copyAfterAdd copies files from the current worktree to the newly created worktree.
+// It is a no-op when running from a bare root (no working tree to copy from).
+// The result is returned even if the copy fails in strict mode.
+func copyAfterAdd(ctx context.Context, ac *addWorktreeContext, dstPath string, copyOpts CopyOptions) (*CopyResult, error) {
+	plan, err := planAddCopy(ctx, ac, dstPath, copyOpts)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to copy files: %w", err)
+	}
+	result, err := plan.Execute(ctx, dstPath, os.Stderr)
+	if err != nil {
+		return result, fmt.Errorf("failed to copy files: %w", err)
+	}
+	return result, nil
+}
+
+// AddWorktree creates a new worktree for the given branch and returns what
+// was copied into it.
+func AddWorktree(ctx context.Context, path, branch string, copyOpts CopyOptions) (*CopyResult, error) {
+	ac, err := prepareAdd(ctx, path, copyOpts)
+	if err != nil {
+		return nil, err
 	}
 
 	cmd, err := gitCommand(ctx, "worktree", "add", path, branch)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
-		return err
+		return nil, err
 	}
 
 	return copyAfterAdd(ctx, ac, path, copyOpts)
 }
 
-// AddWorktreeWithNewBranch creates a new worktree with a new branch.
+// AddWorktreeWithNewBranch creates a new worktree with a new branch and
+// returns what was copied into it.
 // If startPoint is specified, the new branch will be created from that commit/branch.
-func AddWorktreeWithNewBranch(ctx context.Context, path, branch, startPoint string, copyOpts CopyOptions) error {
-	ac, err := prepareAdd(ctx, path)
+func AddWorktreeWithNewBranch(ctx context.Context, path, branch, startPoint string, copyOpts CopyOptions) (*CopyResult, error) {
+	ac, err := prepareAdd(ctx, path, copyOpts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	args := []string{"worktree", "add", "-b", branch, path}
@@ -367,12 +418,12 @@ func AddWorktreeWithNewBranch(ctx context.Context, path, branch, startPoint stri
 
 	cmd, err := gitCommand(ctx, args...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
-		return err
+		return nil, err
 	}
 
 	return copyAfterAdd(ctx, ac, path, copyOpts)
@@ -418,10 +469,12 @@ func initBaseDir(baseDir string) error {
 }
 
 // RunRemover executes a custom remover command to remove a worktree directory.
-// The worktree path is passed safely as a positional argument via sh -c.
-func RunRemover(ctx context.Context, remover string, wtPath string, dir string, w io.Writer) error {
+// The worktree path is passed safely as a positional argument via sh -c, and
+// the GIT_WT_* variables of env are exported to the command.
+func RunRemover(ctx context.Context, remover string, wtPath string, dir string, env HookEnv, w io.Writer) error {
 	cmd := exec.CommandContext(ctx, "sh", "-c", remover+` "$1"`, "--", wtPath)
 	cmd.Dir = dir
+	cmd.Env = env.Environ()
 	cmd.Stdout = w
 	cmd.Stderr = w
 	if err := cmd.Run(); err != nil {
@@ -458,10 +511,39 @@ func RemoveWorktree(ctx context.Context, path string, force bool) error {
 	return cmd.Run()
 }
 
+// LockWorktree locks a worktree with 'git worktree lock' so that it is not
+// pruned, moved or deleted. reason is recorded with the lock if non-empty.
+func LockWorktree(ctx context.Context, path, reason string) error {
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	args = append(args, path)
+
+	cmd, err := gitCommand(ctx, args...)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// UnlockWorktree unlocks a worktree with 'git worktree unlock'.
+func UnlockWorktree(ctx context.Context, path string) error {
+	cmd, err := gitCommand(ctx, "worktree", "unlock", path)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 // MoveWorktree moves a worktree directory from oldPath to newPath using
 // 'git worktree move'. The parent directory of newPath is created if needed.
-// If force is true, '--force' is passed to allow moving worktrees with
-// uncommitted or untracked changes.
+// If force is true, '--force' is passed twice to allow moving worktrees with
+// uncommitted or untracked changes as well as locked worktrees.
 func MoveWorktree(ctx context.Context, oldPath, newPath string, force bool) error {
 	parentDir := filepath.Dir(newPath)
 	if err := os.MkdirAll(parentDir, 0755); err != nil {
@@ -473,7 +555,7 @@ func MoveWorktree(ctx context.Context, oldPath, newPath string, force bool) erro
 
 	args := []string{"worktree", "move"}
 	if force {
-		args = append(args, "--force")
+		args = append(args, "--force", "--force")
 	}
 	args = append(args, oldPath, newPath)
 
@@ -486,6 +568,19 @@ func MoveWorktree(ctx context.Context, oldPath, newPath string, force bool) erro
 	return cmd.Run()
 }
 
+// RepairWorktree runs 'git worktree repair' for the worktree at path, so that
+// it and its administrative files point to each other again after the
+// directory has been moved outside of git.
+func RepairWorktree(ctx context.Context, path string) error {
+	cmd, err := gitCommand(ctx, "worktree", "repair", path)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 // RemoveEmptyParents walks up from startDir removing empty directories until
 // it reaches stopDir (exclusive) or hits a non-empty directory. stopDir is
 // never removed.
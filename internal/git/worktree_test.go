@@ -70,6 +70,71 @@ func TestListWorktrees_Multiple(t *testing.T) {
 	}
 }
 
+func TestListWorktrees_LockedAndPrunable(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	lockedPath := filepath.Join(repo.ParentDir(), "wt-locked")
+	repo.Git("worktree", "add", "-b", "locked", lockedPath)
+	repo.Git("worktree", "lock", "--reason", "on removable drive", lockedPath)
+
+	lockedNoReasonPath := filepath.Join(repo.ParentDir(), "wt-locked-no-reason")
+	repo.Git("worktree", "add", "-b", "locked-no-reason", lockedNoReasonPath)
+	repo.Git("worktree", "lock", lockedNoReasonPath)
+
+	prunablePath := filepath.Join(repo.ParentDir(), "wt-prunable")
+	repo.Git("worktree", "add", "-b", "prunable", prunablePath)
+	if err := os.RemoveAll(prunablePath); err != nil {
+		t.Fatalf("failed to remove worktree directory: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	worktrees, err := ListWorktrees(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := make(map[string]Worktree)
+	for _, wt := range worktrees {
+		got[wt.Branch] = wt
+	}
+
+	tests := []struct {
+		branch         string
+		locked         bool
+		lockReason     string
+		prunable       bool
+		prunableReason bool
+	}{
+		{"main", false, "", false, false},
+		{"locked", true, "on removable drive", false, false},
+		{"locked-no-reason", true, "", false, false},
+		{"prunable", false, "", true, true},
+	}
+	for _, tt := range tests {
+		wt, ok := got[tt.branch]
+		if !ok {
+			t.Errorf("worktree for branch %q not found", tt.branch)
+			continue
+		}
+		if wt.Locked != tt.locked {
+			t.Errorf("%s: Locked = %v, want %v", tt.branch, wt.Locked, tt.locked)
+		}
+		if wt.LockReason != tt.lockReason {
+			t.Errorf("%s: LockReason = %q, want %q", tt.branch, wt.LockReason, tt.lockReason)
+		}
+		if wt.Prunable != tt.prunable {
+			t.Errorf("%s: Prunable = %v, want %v", tt.branch, wt.Prunable, tt.prunable)
+		}
+		if (wt.PrunableReason != "") != tt.prunableReason {
+			t.Errorf("%s: PrunableReason = %q, want non-empty: %v", tt.branch, wt.PrunableReason, tt.prunableReason)
+		}
+	}
+}
+
 func TestCurrentWorktree(t *testing.T) {
 	repo := testutil.NewTestRepo(t)
 	repo.CreateFile("README.md", "# Test")
@@ -277,7 +342,7 @@ func TestAddWorktree(t *testing.T) {
 	defer restore()
 
 	wtPath := filepath.Join(repo.ParentDir(), "worktree-existing")
-	err := AddWorktree(t.Context(), wtPath, "existing-branch", CopyOptions{})
+	_, err := AddWorktree(t.Context(), wtPath, "existing-branch", CopyOptions{})
 	if err != nil {
 		t.Fatalf("AddWorktree failed: %v", err)
 	}
@@ -315,7 +380,7 @@ func TestAddWorktreeWithNewBranch(t *testing.T) {
 	defer restore()
 
 	wtPath := filepath.Join(repo.ParentDir(), "worktree-new")
-	err := AddWorktreeWithNewBranch(t.Context(), wtPath, "new-branch", "", CopyOptions{})
+	_, err := AddWorktreeWithNewBranch(t.Context(), wtPath, "new-branch", "", CopyOptions{})
 	if err != nil {
 		t.Fatalf("AddWorktreeWithNewBranch failed: %v", err)
 	}
@@ -370,7 +435,7 @@ func TestAddWorktree_FromBareRepository(t *testing.T) {
 	}()
 
 	wtPath := filepath.Join(bareRepo.ParentDir(), "wt-existing")
-	err = AddWorktree(t.Context(), wtPath, "main", CopyOptions{})
+	_, err = AddWorktree(t.Context(), wtPath, "main", CopyOptions{})
 	if err != nil {
 		t.Fatalf("AddWorktree from bare repo failed: %v", err)
 	}
@@ -414,7 +479,7 @@ func TestAddWorktreeWithNewBranch_FromBareRepository(t *testing.T) {
 	}()
 
 	wtPath := filepath.Join(bareRepo.ParentDir(), "wt-new-branch")
-	err = AddWorktreeWithNewBranch(t.Context(), wtPath, "new-feature", "", CopyOptions{})
+	_, err = AddWorktreeWithNewBranch(t.Context(), wtPath, "new-feature", "", CopyOptions{})
 	if err != nil {
 		t.Fatalf("AddWorktreeWithNewBranch from bare repo failed: %v", err)
 	}
@@ -444,6 +509,43 @@ func TestAddWorktreeWithNewBranch_FromBareRepository(t *testing.T) {
 	}
 }
 
+func TestAddWorktreeWithNewBranch_FromBareRepositoryWithSource(t *testing.T) {
+	bareRepo := testutil.NewBareTestRepo(t)
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	if err := os.Chdir(bareRepo.Root); err != nil {
+		t.Fatalf("failed to chdir: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(origDir); err != nil {
+			t.Fatalf("failed to restore cwd: %v", err)
+		}
+	}()
+
+	srcPath := filepath.Join(bareRepo.ParentDir(), "wt-main")
+	if _, err := AddWorktree(t.Context(), srcPath, "main", CopyOptions{}); err != nil {
+		t.Fatalf("AddWorktree from bare repo failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcPath, "local.txt"), []byte("local"), 0600); err != nil {
+		t.Fatalf("failed to write local.txt: %v", err)
+	}
+
+	wtPath := filepath.Join(bareRepo.ParentDir(), "wt-new-branch")
+	result, err := AddWorktreeWithNewBranch(t.Context(), wtPath, "new-feature", "", CopyOptions{CopyUntracked: true, Source: srcPath})
+	if err != nil {
+		t.Fatalf("AddWorktreeWithNewBranch from bare repo failed: %v", err)
+	}
+	if result.Copied != 1 {
+		t.Errorf("Copied = %d, want 1", result.Copied)
+	}
+	if content, err := os.ReadFile(filepath.Join(wtPath, "local.txt")); err != nil || string(content) != "local" {
+		t.Errorf("local.txt should be copied from the source worktree, got %q (%v)", content, err)
+	}
+}
+
 func TestFindWorktreeByBranchOrDir_SkipsBare(t *testing.T) {
 	bareRepo := testutil.NewBareTestRepo(t)
 
@@ -559,3 +661,37 @@ func TestRemoveWorktree_Force(t *testing.T) {
 		t.Error("worktree should not exist after force removal")
 	}
 }
+
+func TestLockWorktree(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	wtPath := filepath.Join(repo.ParentDir(), "wt-lock")
+	repo.Git("worktree", "add", "-b", "lock-test", wtPath)
+
+	restore := repo.Chdir()
+	defer restore()
+
+	if err := LockWorktree(t.Context(), wtPath, "release branch"); err != nil {
+		t.Fatalf("LockWorktree failed: %v", err)
+	}
+	wt, err := FindWorktreeByBranch(t.Context(), "lock-test")
+	if err != nil {
+		t.Fatalf("FindWorktreeByBranch failed: %v", err)
+	}
+	if wt == nil || !wt.Locked || wt.LockReason != "release branch" {
+		t.Fatalf("worktree should be locked with reason %q, got %+v", "release branch", wt)
+	}
+
+	if err := UnlockWorktree(t.Context(), wtPath); err != nil {
+		t.Fatalf("UnlockWorktree failed: %v", err)
+	}
+	wt, err = FindWorktreeByBranch(t.Context(), "lock-test")
+	if err != nil {
+		t.Fatalf("FindWorktreeByBranch failed: %v", err)
+	}
+	if wt == nil || wt.Locked {
+		t.Fatalf("worktree should be unlocked, got %+v", wt)
+	}
+}